@@ -11,6 +11,7 @@ require (
 	github.com/schollz/progressbar/v3 v3.13.0
 	github.com/urfave/cli/v2 v2.3.0
 	golang.org/x/term v0.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
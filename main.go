@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/muja/goconfig"
@@ -15,36 +17,155 @@ import (
 	"golang.org/x/term"
 )
 
-func getUserFromGitConfig() (*string, *string, error) {
-	user, _ := user.Current()
-	// don't forget to handle error!
+func readGitConfig() (map[string]string, error) {
+	user, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the user home folder: %w", err)
+	}
 	gitconfig := filepath.Join(user.HomeDir, ".gitconfig")
-	bytes, _ := os.ReadFile(gitconfig)
+	bytes, err := os.ReadFile(gitconfig)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cannot read git config file: %w", err)
+	}
 
 	config, _, err := goconfig.Parse(bytes)
 	if err != nil {
-		// Note: config is non-nil and contains successfully parsed values
-		log.Fatalf("Error on git config file: %s\n", err)
+		return nil, fmt.Errorf("invalid git config file %s: %w", gitconfig, err)
+	}
+	return config, nil
+}
+
+func getUserFromGitConfig() (*string, *string, error) {
+	config, err := readGitConfig()
+	if err != nil {
 		return nil, nil, err
 	}
 	username := config["user.name"]
 	usermail := config["user.email"]
+	if username == "" && usermail == "" {
+		return nil, nil, errors.New("no user.name or user.email in git config, give the user to show or use --count-all")
+	}
 	return &username, &usermail, nil
 }
 
+// getMailmapFile returns the user level mailmap file: the one given as
+// parameter or the `mailmap.file` entry of the git config.
+func getMailmapFile(c *cli.Context) (string, error) {
+	mailmapFile := c.String("mailmap-file")
+	if mailmapFile == "" {
+		config, err := readGitConfig()
+		if err != nil {
+			return "", err
+		}
+		mailmapFile = config["mailmap.file"]
+	}
+	return expandHome(mailmapFile)
+}
+
+// expandHome replaces the `~/` prefix of the path by the user home folder
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	user, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(user.HomeDir, path[2:]), nil
+}
+
+// applyConfig reads the configuration file and sets the flags of the command which are not
+// given on the command line to the values of the selected profile, or to the default ones.
+// The precedence is: command line, then profile, then configuration file defaults,
+// then git config for the user and the mailmap file, then flags default values.
+func applyConfig(c *cli.Context) (*stats.Config, map[string][]string, error) {
+	configFile := c.String("config")
+	optional := !c.IsSet("config")
+	if configFile == "" {
+		defaultFile, err := stats.GetConfigFilePath()
+		if err != nil {
+			return nil, nil, err
+		}
+		configFile = defaultFile
+	}
+	config, err := stats.LoadConfig(configFile, optional)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := config.Values(c.String("profile"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	known := map[string]bool{"user": true, "folders": true}
+	addFlagNames(known, commands())
+	// the configuration file cannot select itself or a profile
+	delete(known, "config")
+	delete(known, "profile")
+	for name, value := range values {
+		if !known[name] {
+			return nil, nil, fmt.Errorf("unknown setting %s in config file %s", name, configFile)
+		}
+		if !hasFlag(c.Command.Flags, name) || c.IsSet(name) {
+			continue
+		}
+		for _, v := range value {
+			if err := c.Set(name, v); err != nil {
+				return nil, nil, fmt.Errorf("invalid value %s for setting %s in config file %s: %w", v, name, configFile, err)
+			}
+		}
+	}
+	return config, values, nil
+}
+
+// addFlagNames adds to `names` the names of the flags of the commands and their subcommands
+func addFlagNames(names map[string]bool, commands []*cli.Command) {
+	for _, command := range commands {
+		for _, flag := range command.Flags {
+			for _, name := range flag.Names() {
+				names[name] = true
+			}
+		}
+		addFlagNames(names, command.Subcommands)
+	}
+}
+
+// hasFlag returns true if one of the flags is named `name`
+func hasFlag(flags []cli.Flag, name string) bool {
+	for _, flag := range flags {
+		for _, flagName := range flag.Names() {
+			if flagName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func commands() []*cli.Command {
 	return []*cli.Command{
 		{
 			Name:    "add-repository",
 			Aliases: []string{"ar"},
 			Usage:   "Add folder of git repository to scan for statistics",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "group",
+					Value: "",
+					Usage: "Group of the added repositories",
+				},
+				&cli.StringSliceFlag{
+					Name:  "tag",
+					Usage: "Tag of the added repositories (can be repeated)",
+				},
+			},
 			Action: func(c *cli.Context) error {
 				if c.NArg() > 0 {
 					argNum := 0
 					for argNum < c.NArg() {
 						arg := c.Args().Get(argNum)
 						if _, err := os.Stat(arg); err == nil {
-							err := addToScan(arg)
+							err := stats.Scan(arg, c.String("group"), c.StringSlice("tag"))
 							if err != nil {
 								return err
 							}
@@ -67,82 +188,357 @@ func commands() []*cli.Command {
 			},
 		},
 		{
-			Name:    "dashboard",
-			Aliases: []string{},
-			Usage:   "Open a dashboard for print statistics",
+			Name:    "remove-repository",
+			Aliases: []string{"rr"},
+			Usage:   "Remove folders of git repositories from the scan list",
 			Action: func(c *cli.Context) error {
-				return argParse(c, true)
+				if c.NArg() == 0 {
+					return errors.New("missing repository to remove")
+				}
+				return stats.RemoveRepositories(c.Args().Slice())
+			},
+		},
+		{
+			Name:  "prune",
+			Usage: "Remove from the scan list the folders which no longer exist or are no longer git repositories",
+			Action: func(c *cli.Context) error {
+				return stats.Prune(c.Bool("dry-run"))
 			},
 			Flags: []cli.Flag{
-				&cli.StringFlag{
-					Name:  "delta",
-					Value: "",
-					Usage: "Delta of starting watch commits",
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Value: false,
+					Usage: "Only list the folders to remove",
 				},
-				&cli.IntFlag{
-					Name:  "weeks",
-					Value: -1,
-					Usage: "Number of weeks to compute",
+			},
+		},
+		{
+			Name:      "relocate",
+			Usage:     "Move the repositories of the scan list located in a folder to another one",
+			ArgsUsage: "<old-prefix> <new-prefix>",
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 2 {
+					return errors.New("usage: relocate <old-prefix> <new-prefix>")
+				}
+				return stats.Relocate(c.Args().Get(0), c.Args().Get(1))
+			},
+		},
+		{
+			Name:  "cache",
+			Usage: "Manage the commits cache used to speed up statistics",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "clear",
+					Usage: "Remove all cached commits",
+					Action: func(c *cli.Context) error {
+						return stats.ClearCache()
+					},
+				},
+				{
+					Name:  "stats",
+					Usage: "Show cached repositories and commits",
+					Action: func(c *cli.Context) error {
+						return stats.CacheStats()
+					},
 				},
+			},
+		},
+		{
+			Name:    "dashboard",
+			Aliases: []string{},
+			Usage:   "Open a dashboard for print statistics",
+			Action: func(c *cli.Context) error {
+				return argParse(c, dashboardMode)
+			},
+			Flags: append(scanFlags(),
 				&cli.BoolFlag{
 					Name:  "merge",
 					Value: false,
 					Usage: "Merge all scanned repository",
 				},
+			),
+		},
+		{
+			Name:    "stat",
+			Aliases: []string{"s"},
+			Usage:   "Email or Name: your@email.com / 'Firstname Name' - show constribution statistics of a user",
+			Action: func(c *cli.Context) error {
+				return argParse(c, statMode)
+			},
+			Flags: append(scanFlags(),
 				&cli.BoolFlag{
-					Name:  "count-all",
+					Name:  "merge",
 					Value: false,
-					Usage: "Force count all users contributions",
+					Usage: "Merge all scanned repository",
 				},
-				&cli.StringSliceFlag{
-					Name:  "file-exclude-pattern",
-					Usage: "File pattern to exclude of contributions statistics",
+				&cli.StringFlag{
+					Name:  "output",
+					Value: "console",
+					Usage: "Output format: console or json",
 				},
-				&cli.StringSliceFlag{
-					Name:  "file-include-pattern",
-					Usage: "File pattern to include of contributions statistics",
+				&cli.BoolFlag{
+					Name:  "by-author",
+					Usage: "Print one heatmap per author, use with --count-all to review all contributors",
+				},
+				&cli.IntFlag{
+					Name:  "top",
+					Usage: "Only print the heatmaps of the N most active authors with --by-author or --languages",
+				},
+				&cli.BoolFlag{
+					Name:  "languages",
+					Usage: "Print the commits and lines per language, in total, per author and per repository",
 				},
+			),
+		},
+		{
+			Name:      "compare",
+			Usage:     "Compare the contributions of several users over the same period",
+			ArgsUsage: "<userA> <userB> [...] [folders]",
+			Action: func(c *cli.Context) error {
+				return argParse(c, compareMode)
 			},
+			Flags: scanFlags(),
 		},
 		{
-			Name:    "stat",
-			Aliases: []string{"s"},
-			Usage:   "Email or Name: your@email.com / 'Firstname Name' - show constribution statistics of a user",
+			Name:      "leaderboard",
+			Usage:     "Rank the authors of the scanned repositories",
+			ArgsUsage: "[users] [folders]",
 			Action: func(c *cli.Context) error {
-				return argParse(c, false)
+				return argParse(c, leaderboardMode)
 			},
-			Flags: []cli.Flag{
+			Flags: append(scanFlags(),
 				&cli.StringFlag{
-					Name:  "delta",
-					Value: "",
-					Usage: "Delta of starting watch commits",
+					Name:  "sort",
+					Value: "commits",
+					Usage: "Ranking value: commits, active-days, additions, deletions, net or files",
 				},
 				&cli.IntFlag{
-					Name:  "weeks",
-					Value: -1,
-					Usage: "Number of weeks to compute",
+					Name:  "top",
+					Value: 10,
+					Usage: "Number of authors ranked, all of them if 0",
 				},
-				&cli.BoolFlag{
-					Name:  "merge",
-					Value: false,
-					Usage: "Merge all scanned repository",
+				&cli.StringFlag{
+					Name:  "output",
+					Value: "console",
+					Usage: "Output format: console or json",
 				},
-				&cli.BoolFlag{
-					Name:  "count-all",
-					Value: false,
-					Usage: "Force count all users contributions",
+			),
+		},
+		{
+			Name:  "report",
+			Usage: "Build a report of contribution statistics",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "html",
+					Usage: "Email or Name: your@email.com / 'Firstname Name' - write a standalone HTML report with the dashboard panels",
+					Action: func(c *cli.Context) error {
+						return argParse(c, reportHTMLMode)
+					},
+					Flags: append(scanFlags(),
+						&cli.StringFlag{
+							Name:    "file",
+							Aliases: []string{"o"},
+							Value:   "report.html",
+							Usage:   "HTML file to write",
+						},
+					),
+				},
+			},
+		},
+		{
+			Name:  "export",
+			Usage: "Export contribution statistics to a file",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "svg",
+					Usage: "Email or Name: your@email.com / 'Firstname Name' - export the contributions calendar of all scanned repositories as SVG",
+					Action: func(c *cli.Context) error {
+						return argParse(c, exportSVGMode)
+					},
+					Flags: append(scanFlags(),
+						&cli.StringFlag{
+							Name:    "file",
+							Aliases: []string{"o"},
+							Value:   "contributions.svg",
+							Usage:   "SVG file to write",
+						},
+					),
 				},
 			},
 		},
 	}
 }
 
-func argParse(c *cli.Context, useDashboard bool) error {
+// scanFlags returns the flags shared by the commands computing statistics
+func scanFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Value:   "",
+			Usage:   "Configuration file (defaults to ~/.config/gitcontribution/config.yaml)",
+			EnvVars: []string{"GITCONTRIBUTION_CONFIG"},
+		},
+		&cli.StringFlag{
+			Name:  "profile",
+			Value: "",
+			Usage: "Profile of the configuration file to use",
+		},
+		&cli.StringFlag{
+			Name:  "delta",
+			Value: "",
+			Usage: "Delta of starting watch commits",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Value: "",
+			Usage: "First day to scan: YYYY-MM-DD, YYYY-MM, YYYY-Qn, YYYY or a relative period (last-quarter, this-year...)",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Value: "",
+			Usage: "Last day to scan, same formats as --since (the last day of the period is used)",
+		},
+		&cli.StringFlag{
+			Name:  "week-start",
+			Value: "monday",
+			Usage: "First day of the weeks: sunday, monday or saturday",
+		},
+		&cli.StringFlag{
+			Name:  "locale",
+			Value: stats.DefaultLocale,
+			Usage: "Language of the months and weekdays names: " + strings.Join(stats.Locales(), ", "),
+		},
+		&cli.StringFlag{
+			Name:  "timezone",
+			Value: stats.AuthorTimezone,
+			Usage: "Time zone of the commits days and hours: an IANA name (Europe/Paris), local, or author to keep each commit time zone",
+		},
+		&cli.StringFlag{
+			Name:  "date-source",
+			Value: "author",
+			Usage: "Commit date used for the scan window and the statistics: author or committer",
+		},
+		&cli.BoolFlag{
+			Name:  "match-committer",
+			Value: false,
+			Usage: "Count the commits applied by the user too (rebased, cherry-picked or merged on behalf of others)",
+		},
+		&cli.StringFlag{
+			Name:  "merges",
+			Value: "include",
+			Usage: "Merge commits counted: include, exclude or only",
+		},
+		&cli.BoolFlag{
+			Name:  "first-parent",
+			Value: false,
+			Usage: "Follow only the first parent of merge commits, ignoring the commits of merged branches",
+		},
+		&cli.StringFlag{
+			Name:  "merge-lines",
+			Value: "auto",
+			Usage: "Lines of merge commits: first-parent (diff against the first parent), skip, or auto (first-parent with --first-parent, skip otherwise)",
+		},
+		&cli.StringFlag{
+			Name:  "theme",
+			Value: stats.DefaultTheme,
+			Usage: "Heatmap colors theme: " + strings.Join(stats.Themes(), ", "),
+		},
+		&cli.StringFlag{
+			Name:  "scale",
+			Value: "fixed",
+			Usage: "Heatmap intensity scale: fixed, quantile or the middle and high thresholds like 3,8",
+		},
+		&cli.IntFlag{
+			Name:  "jobs",
+			Value: runtime.NumCPU(),
+			Usage: "Number of repositories analyzed at the same time",
+		},
+		&cli.BoolFlag{
+			Name:  "fail-on-error",
+			Usage: "Exit with an error status when a repository could not be analyzed",
+		},
+		&cli.StringSliceFlag{
+			Name:  "file-exclude-pattern",
+			Usage: "Files to exclude of contributions statistics: regular expression, glob:<gitignore glob> or git pathspec like :(exclude)vendor/**",
+		},
+		&cli.StringSliceFlag{
+			Name:  "file-include-pattern",
+			Usage: "Files to include in contributions statistics: regular expression, glob:<gitignore glob> or git pathspec like :(glob)src/**",
+		},
+		&cli.IntFlag{
+			Name:  "weeks",
+			Value: -1,
+			Usage: "Number of weeks to compute",
+		},
+		&cli.BoolFlag{
+			Name:  "count-all",
+			Value: false,
+			Usage: "Force count all users contributions",
+		},
+		&cli.StringFlag{
+			Name:  "mailmap-file",
+			Value: "",
+			Usage: "Mailmap file used in addition of the repositories .mailmap (defaults to git config mailmap.file)",
+		},
+		&cli.BoolFlag{
+			Name:  "no-cache",
+			Value: false,
+			Usage: "Read all commits from repositories instead of using the commits cache",
+		},
+		&cli.StringFlag{
+			Name:  "co-author-credit",
+			Value: "full",
+			Usage: "Lines credited to Co-authored-by trailers: full (all lines to each co-author), split (shared between authors) or none",
+		},
+		&cli.BoolFlag{
+			Name:  "all-refs",
+			Value: false,
+			Usage: "Scan the commits of all refs (branches, remote-tracking branches and tags) instead of HEAD only",
+		},
+		&cli.StringSliceFlag{
+			Name:  "branch",
+			Usage: "Glob of the branches to scan instead of HEAD (can be repeated)",
+		},
+		&cli.BoolFlag{
+			Name:  "remote-branches",
+			Value: false,
+			Usage: "Scan the remote-tracking branches too",
+		},
+		&cli.StringSliceFlag{
+			Name:  "group",
+			Usage: "Scan the saved repositories of this group (can be repeated)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tag",
+			Usage: "Scan the saved repositories having this tag (can be repeated)",
+		},
+	}
+}
+
+// runMode is the way statistics are rendered
+type runMode int
+
+const (
+	statMode runMode = iota
+	dashboardMode
+	exportSVGMode
+	reportHTMLMode
+	compareMode
+	leaderboardMode
+)
+
+func argParse(c *cli.Context, mode runMode) error {
 	var folders []string
 	var weeks *int = nil
 	var user *string = nil
+	var users []string
 	var err error
 
+	config, configValues, err := applyConfig(c)
+	if err != nil {
+		return err
+	}
+
 	if c.Int("weeks") > 0 {
 		weeksParam := c.Int("weeks")
 		weeks = &weeksParam
@@ -156,18 +552,48 @@ func argParse(c *cli.Context, useDashboard bool) error {
 				folders = append(folders, arg)
 			} else if errors.Is(err, os.ErrNotExist) {
 				user = &arg
+				users = append(users, arg)
 			}
 			argNum += 1
 		}
 	}
-	if user == nil && !c.Bool("count-all") {
-		_, gitEmail, err := getUserFromGitConfig()
+	// compared and ranked users are only given on the command line
+	rankingMode := mode == compareMode || mode == leaderboardMode
+	if users, ok := configValues["user"]; ok && user == nil && !c.Bool("count-all") && !rankingMode {
+		configUser := strings.Join(users, ",")
+		user = &configUser
+	}
+	if user != nil {
+		expanded := config.ExpandAliases(*user)
+		user = &expanded
+	}
+	if user == nil && !c.Bool("count-all") && !rankingMode {
+		gitName, gitEmail, err := getUserFromGitConfig()
 		if err != nil {
-			panic(err)
+			return err
 		}
 		user = gitEmail
+		if *gitEmail == "" {
+			user = gitName
+		}
 	}
 
+	if len(folders) == 0 && (len(c.StringSlice("group")) > 0 || len(c.StringSlice("tag")) > 0) {
+		// groups are given on the command line or by the profile
+		folders, err = stats.GetGroupFolders(c.StringSlice("group"), c.StringSlice("tag"))
+		if err != nil {
+			return err
+		}
+	}
+	if len(folders) == 0 {
+		for _, folder := range configValues["folders"] {
+			folder, err = expandHome(folder)
+			if err != nil {
+				return err
+			}
+			folders = append(folders, folder)
+		}
+	}
 	if len(folders) == 0 {
 		folders, err = stats.GetFolders()
 		if err != nil {
@@ -175,49 +601,139 @@ func argParse(c *cli.Context, useDashboard bool) error {
 		}
 	}
 
+	mailmapFile, err := getMailmapFile(c)
+	if err != nil {
+		return err
+	}
+
+	output, err := stats.ParseOutputType(c.String("output"))
+	if err != nil {
+		return err
+	}
+	coAuthorCredit, err := stats.ParseCoAuthorCredit(c.String("co-author-credit"))
+	if err != nil {
+		return err
+	}
+	weekStart, err := stats.ParseWeekStart(c.String("week-start"))
+	if err != nil {
+		return err
+	}
+	dateSource, err := stats.ParseDateSource(c.String("date-source"))
+	if err != nil {
+		return err
+	}
+	merges, err := stats.ParseMergesPolicy(c.String("merges"))
+	if err != nil {
+		return err
+	}
+	mergeLines, err := stats.ParseMergeLines(c.String("merge-lines"))
+	if err != nil {
+		return err
+	}
+	patternToExclude := c.StringSlice("file-exclude-pattern")
+	patternToInclude := c.StringSlice("file-include-pattern")
+	if err := stats.CheckFilePatterns(patternToExclude, patternToInclude); err != nil {
+		return err
+	}
+	// the theme and the scale defaults are only read from the configuration file
+	theme := c.String("theme")
+	scale, err := stats.ParseScale(c.String("scale"))
+	if err != nil {
+		return err
+	}
+	refs := stats.RefsSelection{
+		All:            c.Bool("all-refs"),
+		Branches:       c.StringSlice("branch"),
+		RemoteBranches: c.Bool("remote-branches"),
+	}
+	// only the console output has to fit the terminal width
+	fitTerminal := (mode == statMode && output == stats.Console) || mode == compareMode
+
 	durationInWeeks := 0
 	width, _, _ := term.GetSize(0)
 
 	durationInWeeks = 52
 	if weeks != nil {
-		if width < (4**weeks)+16 && !useDashboard {
+		if width < (4**weeks)+16 && fitTerminal {
 			return errors.New("too much data to display in this terminal width")
 		}
 		durationInWeeks = *weeks
 	} else {
 		defaultDuration := (width - 16) / 4
-		if !useDashboard {
+		if fitTerminal {
 			durationInWeeks = defaultDuration
 		}
 	}
 
-	if useDashboard {
-		stats.OpenDashboard(stats.LaunchOptions{
-			User:             user,
-			DurationInWeeks:  durationInWeeks,
-			Folders:          folders,
-			Merge:            false,
-			Delta:            c.String("delta"),
-			Dashboard:        true,
-			PatternToExclude: c.StringSlice("file-exclude-pattern"),
-			PatternToInclude: c.StringSlice("file-include-pattern"),
-		})
-	} else {
-		stats.Launch(stats.LaunchOptions{
-			User:            user,
-			DurationInWeeks: durationInWeeks,
-			Folders:         folders,
-			Merge:           c.Bool("merge"),
-			Delta:           c.String("delta"),
-			Dashboard:       false,
-		})
+	// the options shared by all the modes, each mode only overrides its own ones
+	opts := stats.LaunchOptions{
+		User:             user,
+		DurationInWeeks:  durationInWeeks,
+		Folders:          folders,
+		Merge:            c.Bool("merge"),
+		Delta:            c.String("delta"),
+		Output:           output,
+		MailmapFile:      mailmapFile,
+		NoCache:          c.Bool("no-cache"),
+		PatternToExclude: patternToExclude,
+		PatternToInclude: patternToInclude,
+		CoAuthorCredit:   coAuthorCredit,
+		Refs:             refs,
+		Since:            c.String("since"),
+		Until:            c.String("until"),
+		WeekStart:        weekStart,
+		Locale:           c.String("locale"),
+		Timezone:         c.String("timezone"),
+		DateSource:       dateSource,
+		MatchCommitter:   c.Bool("match-committer"),
+		Merges:           merges,
+		FirstParent:      c.Bool("first-parent"),
+		MergeLines:       mergeLines,
+		Theme:            theme,
+		Scale:            scale,
+		Jobs:             c.Int("jobs"),
+		FailOnError:      c.Bool("fail-on-error"),
+		ByAuthor:         c.Bool("by-author"),
+		TopAuthors:       c.Int("top"),
+		Languages:        c.Bool("languages"),
 	}
 
-	return err
-}
+	switch mode {
+	case dashboardMode:
+		opts.Merge = false
+		opts.Dashboard = true
+		err = stats.OpenDashboard(opts)
+	case exportSVGMode:
+		opts.Merge = true
+		opts.Dashboard = true
+		err = stats.ExportSVG(opts, c.String("file"))
+	case reportHTMLMode:
+		opts.Merge = false
+		opts.Dashboard = true
+		err = stats.ReportHTML(opts, c.String("file"))
+	case compareMode:
+		compared := []string{}
+		for _, u := range users {
+			compared = append(compared, config.ExpandAliases(u))
+		}
+		opts.User = nil
+		opts.Merge = true
+		err = stats.CompareUsers(opts, compared)
+	case leaderboardMode:
+		var sortBy stats.LeaderboardSort
+		sortBy, err = stats.ParseLeaderboardSort(c.String("sort"))
+		if err != nil {
+			return err
+		}
+		// all the given users are ranked, all the authors without users
+		opts.User = config.ExpandUsers(users)
+		opts.Merge = true
+		err = stats.Leaderboard(opts, sortBy)
+	default:
+		err = stats.PrintStats(opts)
+	}
 
-func addToScan(folder string) error {
-	return stats.Scan(folder)
+	return err
 }
 
 func main() {
@@ -227,7 +743,7 @@ func main() {
 		Compiled: time.Now(),
 		Commands: commands(),
 		Action: func(c *cli.Context) error {
-			return argParse(c, false)
+			return argParse(c, statMode)
 		},
 	}
 	err := app.Run(os.Args)
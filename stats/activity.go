@@ -0,0 +1,144 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+)
+
+// Activity summarizes the commits of a result over the scan period
+type Activity struct {
+	Commits    int
+	ActiveDays int
+	// ActiveRatio is the part of the days of the scan period with commits
+	ActiveRatio float64
+	// LongestStreak is the greatest number of consecutive days with commits,
+	// the first one found if several have the same length
+	LongestStreak      int
+	LongestStreakBegin time.Time
+	LongestStreakEnd   time.Time
+	// CurrentStreak is the number of consecutive days with commits until the last day
+	// of the scan, or the day before as long as the last day has no commit yet
+	CurrentStreak       int
+	BusiestDay          time.Time
+	BusiestDayCommits   int
+	CommitsPerActiveDay float64
+	CommitsPerWeek      float64
+	Additions           int
+	Deletions           int
+	// PeakHour is the hour with the most commits, -1 without commits
+	PeakHour int
+	// PeakWeekday is the weekday with the most commits, -1 without commits
+	PeakWeekday time.Weekday
+}
+
+// Activity returns the summary of the commits of the result
+func (r *StatsResult) Activity() Activity {
+	a := Activity{
+		Additions:   r.Additions,
+		Deletions:   r.Deletions,
+		PeakHour:    -1,
+		PeakWeekday: -1,
+	}
+	streak := 0
+	var streakBegin time.Time
+	for day := getBeginningOfDay(r.BeginOfScan); r.inScan(day); day = day.AddDate(0, 0, 1) {
+		commits := r.CommitsOn(day)
+		a.Commits += commits
+		if commits > a.BusiestDayCommits {
+			a.BusiestDay = day
+			a.BusiestDayCommits = commits
+		}
+		if commits == 0 {
+			streak = 0
+			continue
+		}
+		a.ActiveDays++
+		if streak == 0 {
+			streakBegin = day
+		}
+		streak++
+		if streak > a.LongestStreak {
+			a.LongestStreak = streak
+			a.LongestStreakBegin = streakBegin
+			a.LongestStreakEnd = day
+		}
+	}
+
+	day := getBeginningOfDay(r.EndOfScan)
+	if r.CommitsOn(day) == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for ; r.inScan(day) && r.CommitsOn(day) > 0; day = day.AddDate(0, 0, -1) {
+		a.CurrentStreak++
+	}
+
+	if days := r.scanDays(); days > 0 {
+		a.ActiveRatio = float64(a.ActiveDays) / float64(days)
+		a.CommitsPerWeek = float64(a.Commits) * 7 / float64(days)
+	}
+	if a.ActiveDays > 0 {
+		a.CommitsPerActiveDay = float64(a.Commits) / float64(a.ActiveDays)
+	}
+	for hour, commits := range r.HoursCommits {
+		if commits > 0 && (a.PeakHour < 0 || commits > r.HoursCommits[a.PeakHour]) {
+			a.PeakHour = hour
+		}
+	}
+	// weekdays are compared from the first day of the week
+	for _, day := range r.Options.WeekStart.Weekdays() {
+		if r.DayCommits[day] > 0 && (a.PeakWeekday < 0 || r.DayCommits[day] > r.DayCommits[a.PeakWeekday]) {
+			a.PeakWeekday = day
+		}
+	}
+	return a
+}
+
+// lines returns the description of the activity, with the dates in the `locale`
+func (a Activity) lines(locale Locale) []string {
+	if a.Commits == 0 {
+		return []string{"No commits"}
+	}
+	longest := fmt.Sprintf("Longest streak: 1 day, on %s", locale.date(a.LongestStreakBegin))
+	if a.LongestStreak > 1 {
+		longest = fmt.Sprintf("Longest streak: %s, from %s to %s", days(a.LongestStreak), locale.date(a.LongestStreakBegin), locale.date(a.LongestStreakEnd))
+	}
+	return []string{
+		fmt.Sprintf("Commits: %d on %s (%.0f%% of the days)", a.Commits, activeDays(a.ActiveDays), a.ActiveRatio*100),
+		fmt.Sprintf("Average: %.1f per active day, %.1f per week", a.CommitsPerActiveDay, a.CommitsPerWeek),
+		longest,
+		fmt.Sprintf("Current streak: %s", days(a.CurrentStreak)),
+		fmt.Sprintf("Busiest day: %s with %s", locale.date(a.BusiestDay), commitsCount(a.BusiestDayCommits)),
+	}
+}
+
+// days returns the number of days with its unit
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// commitsCount returns the number of commits with its unit
+func commitsCount(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
+
+// activeDays returns the number of active days with its unit
+func activeDays(n int) string {
+	if n == 1 {
+		return "1 active day"
+	}
+	return fmt.Sprintf("%d active days", n)
+}
+
+// printActivity prints the activity summary of the result
+func printActivity(r *StatsResult) {
+	fmt.Println()
+	for _, line := range r.Activity().lines(r.Options.locale()) {
+		fmt.Println(line)
+	}
+}
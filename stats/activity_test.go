@@ -0,0 +1,75 @@
+package stats_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestActivity(tt *testing.T) {
+	t := td.NewT(tt)
+
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, time.Local)
+	}
+	repo := newTestRepository(tt)
+	for i, when := range []time.Time{
+		day(time.March, 3), day(time.March, 4), day(time.March, 5),
+		day(time.March, 10), day(time.March, 10), day(time.March, 10),
+		day(time.March, 30), day(time.March, 31),
+	} {
+		repo.commit("a.txt", fmt.Sprint(i), "Alice", "alice@corp.com", when.Add(10*time.Hour), "commit")
+	}
+
+	activity := func(until string) stats.Activity {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache: true,
+			Folders: []string{repo.path},
+			Since:   "2025-03-01",
+			Until:   until,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+		return results[0].Activity()
+	}
+
+	a := activity("2025-03-31")
+	t.Cmp(a, td.SStruct(stats.Activity{
+		Commits:             8,
+		ActiveDays:          6,
+		LongestStreak:       3,
+		LongestStreakBegin:  day(time.March, 3),
+		LongestStreakEnd:    day(time.March, 5),
+		CurrentStreak:       2,
+		BusiestDay:          day(time.March, 10),
+		BusiestDayCommits:   3,
+		CommitsPerActiveDay: 8.0 / 6,
+		PeakHour:            10,
+		PeakWeekday:         time.Monday,
+	}, td.StructFields{
+		"ActiveRatio":    td.Between(0.193, 0.194),
+		"CommitsPerWeek": td.Between(1.80, 1.81),
+		"Additions":      td.Ignore(),
+		"Deletions":      td.Ignore(),
+	}))
+
+	t.Cmp(activity("2025-04-01").CurrentStreak, 2, "the last day has no commit yet")
+	t.Cmp(activity("2025-04-02").CurrentStreak, 0)
+
+	// a single commit is not pluralized
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		Folders: []string{repo.path},
+		Since:   "2025-03-30",
+		Until:   "2025-03-30",
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	output := captureStdout(tt, func() { stats.PrintResult(results[0]) })
+	t.Cmp(output, td.Contains("Commits: 1 on 1 active day "))
+	t.Cmp(output, td.Contains("Longest streak: 1 day, on "))
+	t.Cmp(output, td.Re(`Busiest day: .* with 1 commit\n`))
+}
@@ -0,0 +1,248 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Analyzer computes the contribution statistics of git repositories.
+// It does not print anything: the results are rendered by the console printer,
+// the dashboard or the exporters.
+type Analyzer struct {
+	options LaunchOptions
+	// OnCommit, when set, is called for each analyzed commit in order to report progress.
+	// It can be called concurrently.
+	OnCommit func()
+}
+
+// NewAnalyzer returns an analyzer of the repositories described by `opts`
+func NewAnalyzer(opts LaunchOptions) *Analyzer {
+	return &Analyzer{options: opts}
+}
+
+// Run analyzes the repositories and returns one result per folder, or a single result
+// for all the folders in merge mode. The repositories are analyzed by a pool of
+// `Jobs` workers, a merged result combines the results of each repository.
+// The errors of a repository are reported in the Error field of its result,
+// Run only returns an error on invalid options or when the context is done.
+func (a *Analyzer) Run(ctx context.Context) ([]*StatsResult, error) {
+	opts := a.options
+	patterns, err := compilePatterns(opts.PatternToExclude, opts.PatternToInclude)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.Refs.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := ParseLocale(opts.Locale); err != nil {
+		return nil, err
+	}
+	if _, err := loadTimezone(opts.Timezone); err != nil {
+		return nil, err
+	}
+	if _, err := ParseTheme(opts.Theme); err != nil {
+		return nil, err
+	}
+	// the scan window is computed once, the same for all the repositories
+	window := &StatsResult{}
+	if err := populateDurationInDays(opts, window); err != nil {
+		return nil, err
+	}
+
+	if opts.Merge {
+		merged := a.newResult(opts.Folders, window)
+		// each repository is analyzed apart, then the results are combined
+		parts := make([]*StatsResult, 0, len(opts.Folders))
+		for _, folder := range opts.Folders {
+			parts = append(parts, merged.part(folder))
+		}
+		a.analyzeAll(ctx, parts, patterns)
+		merged.combine(parts)
+		return []*StatsResult{merged}, ctx.Err()
+	}
+
+	results := []*StatsResult{}
+	for _, folder := range opts.Folders {
+		results = append(results, a.newResult([]string{folder}, window))
+	}
+	a.analyzeAll(ctx, results, patterns)
+	return results, ctx.Err()
+}
+
+// jobs returns the number of repositories analyzed at the same time
+func (o LaunchOptions) jobs() int {
+	if o.Jobs > 0 {
+		return o.Jobs
+	}
+	return runtime.NumCPU()
+}
+
+// analyzeAll fills the results with a bounded pool of workers sharing the compiled
+// files patterns, the results already in error are skipped
+func (a *Analyzer) analyzeAll(ctx context.Context, results []*StatsResult, patterns *filePatterns) {
+	queue := make(chan *StatsResult)
+	var wg sync.WaitGroup
+	for i := 0; i < a.options.jobs() && i < len(results); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range queue {
+				analyze(ctx, r, patterns, a.OnCommit)
+			}
+		}()
+	}
+	for _, r := range results {
+		if r.Error == nil {
+			queue <- r
+		}
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// newResult returns the result to fill with the statistics of `folders`
+// over the scan window of `window`
+func (a *Analyzer) newResult(folders []string, window *StatsResult) *StatsResult {
+	opts := a.options
+	r := &StatsResult{
+		Options: StatsOptions{
+			EmailOrUsername:      opts.User,
+			DurationParamInWeeks: opts.DurationInWeeks,
+			Folders:              folders,
+			Delta:                opts.Delta,
+			Silent:               opts.Dashboard || opts.Output == JSON,
+			PatternToExclude:     opts.PatternToExclude,
+			PatternToInclude:     opts.PatternToInclude,
+			MailmapFile:          opts.MailmapFile,
+			NoCache:              opts.NoCache,
+			CacheDir:             opts.CacheDir,
+			CoAuthorCredit:       opts.CoAuthorCredit,
+			Refs:                 opts.Refs,
+			Since:                opts.Since,
+			Until:                opts.Until,
+			WeekStart:            opts.WeekStart,
+			Locale:               opts.Locale,
+			Timezone:             opts.Timezone,
+			DateSource:           opts.DateSource,
+			MatchCommitter:       opts.MatchCommitter,
+			Merges:               opts.Merges,
+			FirstParent:          opts.FirstParent,
+			MergeLines:           opts.MergeLines,
+			Theme:                opts.Theme,
+			Scale:                opts.Scale,
+		},
+		BeginOfScan:    window.BeginOfScan,
+		EndOfScan:      window.EndOfScan,
+		DurationInDays: window.DurationInDays,
+		Folder:         strings.Join(folders, ","),
+	}
+	return r
+}
+
+// analyze fills the result with the statistics of its folders,
+// the files patterns are compiled from the result options if nil
+func analyze(ctx context.Context, r *StatsResult, patterns *filePatterns, onCommit func()) {
+	if onCommit == nil {
+		onCommit = func() {}
+	}
+	r.Folder = strings.Join(r.Options.Folders, ",")
+	if patterns == nil {
+		var err error
+		patterns, err = compilePatterns(r.Options.PatternToExclude, r.Options.PatternToInclude)
+		if err != nil {
+			r.Error = err
+			return
+		}
+	}
+	err := processRepositories(ctx, r, patterns, onCommit)
+	if err != nil {
+		r.Error = err
+	}
+}
+
+// part returns the result to fill with the statistics of the `folder` only,
+// sharing the options and the scan window of the merged result
+func (r *StatsResult) part(folder string) *StatsResult {
+	part := &StatsResult{
+		Options:        r.Options,
+		BeginOfScan:    r.BeginOfScan,
+		EndOfScan:      r.EndOfScan,
+		DurationInDays: r.DurationInDays,
+		Folder:         folder,
+	}
+	part.Options.Folders = []string{folder}
+	return part
+}
+
+// combine fills the result with the sum of the statistics of the `parts`
+func (r *StatsResult) combine(parts []*StatsResult) {
+	r.Commits = make(map[int]int, r.scanDays())
+	r.AuthorsEditions = make(map[string]map[string]int)
+	r.AuthorsCommits = make(map[string]map[int]int)
+	r.AuthorsFiles = make(map[string]map[string]bool)
+	r.Languages = Languages{}
+	r.AuthorsLanguages = make(map[string]Languages)
+	r.RepositoriesLanguages = make(map[string]Languages)
+	r.RequestedAuthors = make(map[string]bool)
+	for day := getBeginningOfDay(r.BeginOfScan); r.inScan(day); day = day.AddDate(0, 0, 1) {
+		r.Commits[r.commitsKey(day)] = 0
+	}
+	var errs []error
+	for _, part := range parts {
+		if part.Error != nil {
+			errs = append(errs, part.Error)
+		}
+		for key, commits := range part.Commits {
+			r.Commits[key] += commits
+		}
+		r.Additions += part.Additions
+		r.Deletions += part.Deletions
+		for hour, commits := range part.HoursCommits {
+			r.HoursCommits[hour] += commits
+		}
+		for day, commits := range part.DayCommits {
+			r.DayCommits[day] += commits
+		}
+		for author, editions := range part.AuthorsEditions {
+			if r.AuthorsEditions[author] == nil {
+				r.AuthorsEditions[author] = make(map[string]int, 2)
+			}
+			for kind, lines := range editions {
+				r.AuthorsEditions[author][kind] += lines
+			}
+		}
+		for author, commits := range part.AuthorsCommits {
+			if r.AuthorsCommits[author] == nil {
+				r.AuthorsCommits[author] = make(map[int]int)
+			}
+			for key, count := range commits {
+				r.AuthorsCommits[author][key] += count
+			}
+		}
+		for author, files := range part.AuthorsFiles {
+			if r.AuthorsFiles[author] == nil {
+				r.AuthorsFiles[author] = make(map[string]bool)
+			}
+			for file := range files {
+				r.AuthorsFiles[author][file] = true
+			}
+		}
+		r.Languages.add(part.Languages)
+		for author, languages := range part.AuthorsLanguages {
+			if r.AuthorsLanguages[author] == nil {
+				r.AuthorsLanguages[author] = Languages{}
+			}
+			r.AuthorsLanguages[author].add(languages)
+		}
+		for repository, languages := range part.RepositoriesLanguages {
+			r.RepositoriesLanguages[repository] = languages
+		}
+		for author := range part.RequestedAuthors {
+			r.RequestedAuthors[author] = true
+		}
+	}
+	r.Error = errors.Join(errs...)
+}
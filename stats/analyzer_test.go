@@ -0,0 +1,95 @@
+package stats_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestAnalyzerRun(tt *testing.T) {
+	t := td.NewT(tt)
+
+	var commits int64
+	analyzer := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache:         true,
+		User:            nil,
+		DurationInWeeks: 4,
+		Folders:         append(currentRepo, "missing-folder"),
+	})
+	analyzer.OnCommit = func() {
+		atomic.AddInt64(&commits, 1)
+	}
+	results, err := analyzer.Run(context.Background())
+	t.CmpNoError(err)
+	t.Cmp(results, td.Len(2))
+	t.CmpNoError(results[0].Error)
+	t.Cmp(results[1].Error, td.Contains("not a repository"))
+
+	total := 0
+	for _, c := range results[0].Commits {
+		total += c
+	}
+	t.Cmp(int(commits), td.Gte(total))
+}
+
+func TestAnalyzerInvalidOptions(tt *testing.T) {
+	t := td.NewT(tt)
+
+	analyzer := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache:          true,
+		Folders:          currentRepo,
+		PatternToExclude: []string{"("},
+	})
+	_, err := analyzer.Run(context.Background())
+	t.CmpError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	analyzer = stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache:         true,
+		DurationInWeeks: 4,
+		Folders:         currentRepo,
+	})
+	_, err = analyzer.Run(ctx)
+	t.Cmp(err, context.Canceled)
+}
+
+func TestAnalyzerMergeJobs(tt *testing.T) {
+	t := td.NewT(tt)
+
+	when := time.Date(2025, time.May, 14, 10, 0, 0, 0, time.UTC)
+	var folders []string
+	for i := 0; i < 3; i++ {
+		repo := newTestRepository(tt)
+		repo.commit("a.txt", "a\nb\n", "Alice", "alice@corp.com", when, "first")
+		repo.commit("b.txt", "c\n", "Bob", "bob@corp.com", when.AddDate(0, 0, i), "second")
+		folders = append(folders, repo.path)
+	}
+
+	for _, jobs := range []int{0, 1, 2, 8} {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache: true,
+			Folders: append(folders, "missing-folder"),
+			Merge:   true,
+			Since:   "2025-05",
+			Until:   "2025-05",
+			Jobs:    jobs,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.Cmp(results, td.Len(1))
+		r := results[0]
+		t.Cmp(r.Error, td.Contains("missing-folder"), "jobs %d", jobs)
+		t.Cmp(r.Options.Folders, td.Len(4))
+		t.Cmp(r.CommitsOn(when), 4)
+		t.Cmp(r.CommitsOn(when.AddDate(0, 0, 1)), 1)
+		t.Cmp(r.CommitsOn(when.AddDate(0, 0, 2)), 1)
+		t.Cmp(r.HoursCommits[10], 6)
+		t.Cmp(r.AuthorsEditions["Alice"]["additions"], 6)
+		t.Cmp(r.AuthorsEditions["Bob"]["additions"], 3)
+		t.Cmp(r.Commits, td.Len(31))
+	}
+}
@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AuthorActivity is the activity of an author over the scan period
+type AuthorActivity struct {
+	Author     string
+	Commits    int
+	Additions  int
+	Deletions  int
+	ActiveDays int
+	// Files is the number of distinct files edited
+	Files int
+}
+
+// Net returns the number of lines added minus the number of lines deleted
+func (a AuthorActivity) Net() int {
+	return a.Additions - a.Deletions
+}
+
+// Authors returns the activity of the authors of the result, sorted by decreasing
+// number of commits, only the `top` first ones if positive
+func (r *StatsResult) Authors(top int) []AuthorActivity {
+	authors := []AuthorActivity{}
+	for author, days := range r.AuthorsCommits {
+		activity := AuthorActivity{
+			Author:    author,
+			Additions: r.AuthorsEditions[author]["additions"],
+			Deletions: r.AuthorsEditions[author]["deletions"],
+			Files:     len(r.AuthorsFiles[author]),
+		}
+		for _, commits := range days {
+			activity.Commits += commits
+			if commits > 0 {
+				activity.ActiveDays++
+			}
+		}
+		authors = append(authors, activity)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Commits != authors[j].Commits {
+			return authors[i].Commits > authors[j].Commits
+		}
+		return authors[i].Author < authors[j].Author
+	})
+	if top > 0 && len(authors) > top {
+		authors = authors[:top]
+	}
+	return authors
+}
+
+// authorResult returns the result restricted to the commits of the `author`
+func (r *StatsResult) authorResult(author string) *StatsResult {
+	authorResult := &StatsResult{
+		Options:         r.Options,
+		BeginOfScan:     r.BeginOfScan,
+		EndOfScan:       r.EndOfScan,
+		DurationInDays:  r.DurationInDays,
+		Folder:          r.Folder,
+		Commits:         make(map[int]int, len(r.Commits)),
+		AuthorsEditions: map[string]map[string]int{author: r.AuthorsEditions[author]},
+		AuthorsCommits:  map[string]map[int]int{author: r.AuthorsCommits[author]},
+		AuthorsFiles:    map[string]map[string]bool{author: r.AuthorsFiles[author]},
+	}
+	for key := range r.Commits {
+		authorResult.Commits[key] = r.AuthorsCommits[author][key]
+	}
+	return authorResult
+}
+
+// PrintAuthors prints the heatmap of each author of the result,
+// only the `top` most active ones if positive
+func PrintAuthors(r *StatsResult, top int) {
+	printHeader(r)
+	all := r.Authors(0)
+	authors := r.Authors(top)
+	if len(authors) < len(all) {
+		fmt.Printf("Top %d of %d authors\n\n", len(authors), len(all))
+	}
+	for _, a := range authors {
+		Print(Header, a.Author)
+		fmt.Printf(": %s, ", commitsCount(a.Commits))
+		Print(Addition, fmt.Sprintf("+%d", a.Additions))
+		fmt.Printf(" / ")
+		Print(Deletion, fmt.Sprintf("-%d", a.Deletions))
+		fmt.Printf(", %s\n", activeDays(a.ActiveDays))
+		StatsResultConsolePrinter{Console}.print(r.authorResult(a.Author), -1)
+		fmt.Println()
+	}
+}
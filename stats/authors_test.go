@@ -0,0 +1,46 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestAuthors(tt *testing.T) {
+	t := td.NewT(tt)
+
+	monday := time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC)
+	repo := newTestRepository(tt)
+	repo.commit("a.txt", "a\nb\n", "Alice", "alice@corp.com", monday, "first")
+	repo.commit("a.txt", "a\n", "Alice", "alice@corp.com", monday.Add(time.Hour), "second")
+	repo.commit("b.txt", "b\n", "Alice", "alice@corp.com", monday.AddDate(0, 0, 2), "third")
+	repo.commit("c.txt", "c\nd\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 3), "pair\n\nCo-authored-by: Carol <carol@corp.com>")
+
+	other := newTestRepository(tt)
+	other.commit("d.txt", "d\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 4), "other")
+	other.commit("e.txt", "e\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 4), "other")
+
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		Folders: []string{repo.path, other.path},
+		Merge:   true,
+		Since:   "2025-02",
+		Until:   "2025-02",
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	t.CmpNoError(results[0].Error)
+
+	r := results[0]
+	t.Cmp(r.Authors(0), []stats.AuthorActivity{
+		{Author: "Alice", Commits: 3, Additions: 3, Deletions: 1, ActiveDays: 2, Files: 2},
+		{Author: "Bob", Commits: 3, Additions: 4, Deletions: 0, ActiveDays: 2, Files: 3},
+		{Author: "Carol", Commits: 1, Additions: 2, Deletions: 0, ActiveDays: 1, Files: 1},
+	})
+	t.Cmp(r.Authors(1), td.Len(1))
+	t.Cmp(r.CommitsOn(monday), 2)
+	t.Cmp(r.CommitsOn(monday.AddDate(0, 0, 4)), 2)
+	t.Cmp(r.Authors(5), td.Len(3))
+}
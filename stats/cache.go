@@ -0,0 +1,421 @@
+package stats
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// cacheVersion must be incremented each time the cached data layout changes,
+// older cache files are then ignored.
+const cacheVersion = 3
+
+// FileStat holds the lines edited on a file by a commit.
+type FileStat struct {
+	Name      string `json:"name"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// CommitRecord holds the data of a commit needed to compute statistics.
+type CommitRecord struct {
+	Hash           string     `json:"hash"`
+	AuthorName     string     `json:"authorName"`
+	AuthorEmail    string     `json:"authorEmail"`
+	AuthorWhen     time.Time  `json:"authorWhen"`
+	CommitterName  string     `json:"committerName"`
+	CommitterEmail string     `json:"committerEmail"`
+	CommitterWhen  time.Time  `json:"committerWhen"`
+	Parents        []string   `json:"parents,omitempty"`
+	CoAuthors      []Identity `json:"coAuthors,omitempty"`
+	Files          []FileStat `json:"files,omitempty"`
+	// HasStats is false until the files statistics are computed,
+	// they are only computed for commits matching the filters.
+	HasStats bool `json:"hasStats"`
+}
+
+// repositoryCache is the content of a cache file: all the commits reachable
+// from Heads committed since Since. There is one cache file per repository
+// and refs selection.
+type repositoryCache struct {
+	Version int                      `json:"version"`
+	Path    string                   `json:"path"`
+	Refs    string                   `json:"refs,omitempty"`
+	Heads   map[string]string        `json:"heads"`
+	Since   time.Time                `json:"since"`
+	Commits map[string]*CommitRecord `json:"commits"`
+}
+
+// commitCache is the on-disk cache of the commits of one repository.
+type commitCache struct {
+	file     string
+	data     repositoryCache
+	dirty    bool
+	disabled bool
+}
+
+// GetCacheDir returns the folder containing the commits cache files.
+func GetCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitcontribution"), nil
+}
+
+// openCommitCache loads the cache of the repository located at `path` from the
+// folder `dir`, the one returned by GetCacheDir if empty.
+// A missing, outdated or unreadable cache file is replaced by an empty cache.
+func openCommitCache(path string, refs RefsSelection, dir string, disabled bool) *commitCache {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+	cc := &commitCache{
+		disabled: disabled,
+		data:     newRepositoryCache(absPath, refs.String()),
+	}
+	if disabled {
+		return cc
+	}
+	if dir == "" {
+		dir, err = GetCacheDir()
+		if err != nil {
+			cc.disabled = true
+			return cc
+		}
+	}
+	key := absPath
+	if refs.String() != "" {
+		key += "\x00" + refs.String()
+	}
+	sum := sha1.Sum([]byte(key))
+	cc.file = filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+
+	content, err := os.ReadFile(cc.file)
+	if err != nil {
+		return cc
+	}
+	var data repositoryCache
+	if err := json.Unmarshal(content, &data); err != nil || data.Version != cacheVersion || data.Path != absPath || data.Refs != refs.String() {
+		return cc
+	}
+	if data.Commits == nil {
+		data.Commits = make(map[string]*CommitRecord)
+	}
+	cc.data = data
+	return cc
+}
+
+func newRepositoryCache(path string, refs string) repositoryCache {
+	return repositoryCache{
+		Version: cacheVersion,
+		Path:    path,
+		Refs:    refs,
+		Heads:   make(map[string]string),
+		Commits: make(map[string]*CommitRecord),
+	}
+}
+
+// save writes the cache file if it has been modified.
+func (cc *commitCache) save() error {
+	if cc.disabled || !cc.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cc.file), 0755); err != nil {
+		return err
+	}
+	content, err := json.Marshal(cc.data)
+	if err != nil {
+		return err
+	}
+	// write then rename, so that concurrent runs never read a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(cc.file), filepath.Base(cc.file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	cc.dirty = false
+	return os.Rename(tmp.Name(), cc.file)
+}
+
+// covers returns true if the cached commits include every commit
+// reachable from the cached heads since `since`.
+func (cc *commitCache) covers(since time.Time) bool {
+	return len(cc.data.Heads) > 0 && !cc.data.Since.IsZero() && !cc.data.Since.After(since)
+}
+
+func newCommitRecord(c *object.Commit) *CommitRecord {
+	var parents []string
+	for _, parent := range c.ParentHashes {
+		parents = append(parents, parent.String())
+	}
+	return &CommitRecord{
+		Hash:           c.Hash.String(),
+		AuthorName:     c.Author.Name,
+		AuthorEmail:    c.Author.Email,
+		AuthorWhen:     c.Author.When,
+		CommitterName:  c.Committer.Name,
+		CommitterEmail: c.Committer.Email,
+		CommitterWhen:  c.Committer.When,
+		Parents:        parents,
+		CoAuthors:      ParseCoAuthors(c.Message),
+	}
+}
+
+// history iterates over the commits of a repository, only walking the git
+// history for the commits not already in cache.
+type history struct {
+	repo       *git.Repository
+	refs       RefsSelection
+	cache      *commitCache
+	dateSource DateSource
+	// firstParent only follows the first parent of the merge commits
+	firstParent bool
+}
+
+// forEach calls `cb` once for each commit reachable from the selected refs
+// and dated between `since` and `until`, according to the history date source.
+func (h *history) forEach(since time.Time, until time.Time, cb func(*CommitRecord) error) error {
+	heads, err := resolveHeads(h.repo, h.refs)
+	if err != nil {
+		return err
+	}
+
+	if h.cache.covers(since) && h.isFastForward(heads) {
+		err = h.walkNewCommits(heads)
+	} else {
+		err = h.walkAll(heads, since)
+	}
+	if err != nil {
+		return err
+	}
+	hashes := make(map[string]string, len(heads))
+	for name, commit := range heads {
+		hashes[name] = commit.Hash.String()
+	}
+	if !reflect.DeepEqual(h.cache.data.Heads, hashes) {
+		h.cache.data.Heads = hashes
+		h.cache.dirty = true
+	}
+
+	var mainline map[string]bool
+	if h.firstParent {
+		mainline = h.firstParentCommits(heads)
+	}
+	for _, record := range h.cache.data.Commits {
+		if date := record.date(h.dateSource); date.Before(since) || date.After(until) {
+			continue
+		}
+		if mainline != nil && !mainline[record.Hash] {
+			continue
+		}
+		if err := cb(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstParentCommits returns the hashes of the cached commits reachable from `heads`
+// following only the first parent of the merge commits
+func (h *history) firstParentCommits(heads map[string]*object.Commit) map[string]bool {
+	mainline := make(map[string]bool)
+	for _, head := range heads {
+		hash := head.Hash.String()
+		for !mainline[hash] {
+			record, ok := h.cache.data.Commits[hash]
+			if !ok {
+				// older than the cached commits
+				break
+			}
+			mainline[hash] = true
+			if len(record.Parents) == 0 {
+				break
+			}
+			hash = record.Parents[0]
+		}
+	}
+	return mainline
+}
+
+// isFastForward returns true if each cached head is an ancestor of the current head
+// of the same ref, meaning that all the cached commits are still reachable.
+func (h *history) isFastForward(heads map[string]*object.Commit) bool {
+	for name, cachedHead := range h.cache.data.Heads {
+		head, ok := heads[name]
+		if !ok {
+			return false
+		}
+		if cachedHead == head.Hash.String() {
+			continue
+		}
+		cachedCommit, err := h.repo.CommitObject(plumbing.NewHash(cachedHead))
+		if err != nil {
+			return false
+		}
+		isAncestor, err := cachedCommit.IsAncestor(head)
+		if err != nil || !isAncestor {
+			return false
+		}
+	}
+	return true
+}
+
+// walkNewCommits adds to the cache the commits reachable from `heads`
+// but not from the cached heads.
+func (h *history) walkNewCommits(heads map[string]*object.Commit) error {
+	var ignore []plumbing.Hash
+	for _, cachedHead := range h.cache.data.Heads {
+		ignore = append(ignore, plumbing.NewHash(cachedHead))
+	}
+	return h.walk(heads, ignore, func(c *object.Commit) {
+		if c.Committer.When.Before(h.cache.data.Since) {
+			return
+		}
+		if _, ok := h.cache.data.Commits[c.Hash.String()]; !ok {
+			h.cache.data.Commits[c.Hash.String()] = newCommitRecord(c)
+			h.cache.dirty = true
+		}
+	})
+}
+
+// walkAll rebuilds the cached commits list walking the whole history since `since`,
+// the statistics of the commits already cached are kept.
+func (h *history) walkAll(heads map[string]*object.Commit, since time.Time) error {
+	commits := make(map[string]*CommitRecord)
+	err := h.walk(heads, nil, func(c *object.Commit) {
+		if c.Committer.When.Before(since) {
+			return
+		}
+		record, ok := h.cache.data.Commits[c.Hash.String()]
+		if !ok {
+			record = newCommitRecord(c)
+		}
+		commits[c.Hash.String()] = record
+	})
+	if err != nil {
+		return err
+	}
+	h.cache.data.Commits = commits
+	h.cache.data.Since = since
+	h.cache.dirty = true
+	return nil
+}
+
+// walk calls `cb` once for each commit reachable from `heads` and not from `ignore`,
+// the commits reachable from several heads are visited once
+func (h *history) walk(heads map[string]*object.Commit, ignore []plumbing.Hash, cb func(*object.Commit)) error {
+	seen := make(map[plumbing.Hash]bool)
+	for _, head := range heads {
+		iterator := object.NewCommitPreorderIter(head, seen, ignore)
+		err := iterator.ForEach(func(c *object.Commit) error {
+			seen[c.Hash] = true
+			cb(c)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stats returns the files statistics of the commit, computing them if not cached.
+func (h *history) stats(ctx context.Context, record *CommitRecord) ([]FileStat, error) {
+	if record.HasStats {
+		return record.Files, nil
+	}
+	c, err := h.repo.CommitObject(plumbing.NewHash(record.Hash))
+	if err != nil {
+		return nil, err
+	}
+	stats, err := c.StatsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record.Files = make([]FileStat, 0, len(stats))
+	for _, stat := range stats {
+		record.Files = append(record.Files, FileStat{
+			Name:      stat.Name,
+			Additions: stat.Addition,
+			Deletions: stat.Deletion,
+		})
+	}
+	record.HasStats = true
+	h.cache.dirty = true
+	return record.Files, nil
+}
+
+// ClearCache removes all the commits cache files.
+func ClearCache() error {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Cache %s cleared\n", dir)
+	return nil
+}
+
+// CacheStats prints the repositories cached and their number of commits.
+func CacheStats() error {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	var totalSize int64
+	var totalCommits int
+	fmt.Printf("Cache folder: %s\n\n", dir)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		filePath := filepath.Join(dir, file.Name())
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		var data repositoryCache
+		if err := json.Unmarshal(content, &data); err != nil || data.Version != cacheVersion {
+			fmt.Printf("- %s: outdated cache file\n", file.Name())
+			continue
+		}
+		totalSize += int64(len(content))
+		totalCommits += len(data.Commits)
+		refs := ""
+		if data.Refs != "" {
+			refs = fmt.Sprintf(" (%s)", data.Refs)
+		}
+		fmt.Printf("- %s%s: %d commits since %s\n", data.Path, refs, len(data.Commits), data.Since.Format("January 02, 2006"))
+	}
+	fmt.Printf("\n%d commits cached, %d KB\n", totalCommits, totalSize/1024)
+	return nil
+}
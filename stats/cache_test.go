@@ -0,0 +1,140 @@
+package stats_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestCachedStatsAreIdentical(tt *testing.T) {
+	t := td.NewT(tt)
+
+	options := stats.LaunchOptions{
+		User:            nil,
+		DurationInWeeks: 52,
+		Folders:         currentRepo,
+		Dashboard:       true,
+		NoCache:         true,
+		CacheDir:        tt.TempDir(),
+	}
+	expected := stats.Launch(options)
+	t.CmpNoError(expected[0].Error)
+
+	options.NoCache = false
+	for run := 0; run < 2; run++ {
+		r := stats.Launch(options)
+		t.CmpNoError(r[0].Error)
+		t.Cmp(r[0].Commits, expected[0].Commits)
+		t.Cmp(r[0].AuthorsEditions, expected[0].AuthorsEditions)
+	}
+}
+
+func TestMissingDiffIsNotCounted(tt *testing.T) {
+	t := td.NewT(tt)
+
+	when := time.Date(2025, time.July, 1, 10, 0, 0, 0, time.Local)
+	repo := newTestRepository(tt)
+	repo.commit("a.txt", "a\n", "Alice", "alice@corp.com", when, "first")
+	hash := repo.commit("a.txt", "b\n", "Alice", "alice@corp.com", when.Add(time.Hour), "second")
+
+	// remove the blob of the second commit, its diff cannot be computed
+	commit, err := repo.repo.CommitObject(hash)
+	t.CmpNoError(err)
+	file, err := commit.File("a.txt")
+	t.CmpNoError(err)
+	blob := file.Hash.String()
+	t.CmpNoError(os.Remove(filepath.Join(repo.path, ".git", "objects", blob[:2], blob[2:])))
+
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{
+		Folders: []string{repo.path},
+		Since:   "2025-07",
+		Until:   "2025-07",
+		NoCache: true,
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	t.Cmp(results[0].Error, td.Contains("cannot compute the changes of commit "+hash.String()))
+}
+
+func TestCacheFollowsHistory(tt *testing.T) {
+	t := td.NewT(tt)
+	cacheDir := tt.TempDir()
+
+	june := time.Date(2025, time.June, 10, 10, 0, 0, 0, time.Local)
+	july := time.Date(2025, time.July, 1, 10, 0, 0, 0, time.Local)
+	repo := newTestRepository(tt)
+	repo.commit("a.txt", "a\n", "Alice", "alice@corp.com", june, "june")
+	first := repo.commit("a.txt", "a\nb\n", "Alice", "alice@corp.com", july, "first")
+	repo.commit("b.txt", "c\n", "Bob", "bob@corp.com", july.AddDate(0, 0, 1), "second")
+
+	// run returns the number of commits and lines found with the cache,
+	// checking that they are the ones found without it
+	run := func(since string, refs stats.RefsSelection) (int, int) {
+		tt.Helper()
+		var counts [2][2]int
+		for i, noCache := range []bool{true, false} {
+			results, err := stats.NewAnalyzer(stats.LaunchOptions{
+				Folders:  []string{repo.path},
+				Since:    since,
+				Until:    "2025-07",
+				Refs:     refs,
+				NoCache:  noCache,
+				CacheDir: cacheDir,
+			}).Run(context.Background())
+			t.CmpNoError(err)
+			t.CmpNoError(results[0].Error)
+			for _, commits := range results[0].Commits {
+				counts[i][0] += commits
+			}
+			counts[i][1] = results[0].Additions + results[0].Deletions
+		}
+		t.Cmp(counts[1], counts[0], "cached statistics are the uncached ones")
+		return counts[1][0], counts[1][1]
+	}
+	cacheFiles := func() int {
+		files, _ := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+		return len(files)
+	}
+	current := stats.RefsSelection{}
+
+	commits, lines := run("2025-07", current)
+	t.Cmp(commits, 2)
+	t.Cmp(lines, 2)
+	t.Cmp(cacheFiles(), 1)
+
+	// new commits appended on the branch
+	repo.commit("b.txt", "c\nd\ne\n", "Bob", "bob@corp.com", july.AddDate(0, 0, 2), "third")
+	commits, lines = run("2025-07", current)
+	t.Cmp(commits, 3)
+	t.Cmp(lines, 4)
+
+	// the branch is reset and rewritten, the dropped commits are not counted anymore
+	repo.reset(first)
+	repo.commit("c.txt", "f\n", "Carol", "carol@corp.com", july.AddDate(0, 0, 3), "rewritten")
+	commits, lines = run("2025-07", current)
+	t.Cmp(commits, 2)
+	t.Cmp(lines, 2)
+
+	// the window is widened to the commits older than the cached ones, then narrowed
+	commits, _ = run("2025-06", current)
+	t.Cmp(commits, 3)
+	commits, _ = run("2025-07", current)
+	t.Cmp(commits, 2)
+	t.Cmp(cacheFiles(), 1)
+
+	// each refs selection has its own cache
+	repo.checkout("feature", true)
+	repo.commit("d.txt", "g\n", "Dan", "dan@corp.com", july.AddDate(0, 0, 4), "feature")
+	repo.checkout("master", false)
+	commits, _ = run("2025-07", current)
+	t.Cmp(commits, 2)
+	commits, _ = run("2025-07", stats.RefsSelection{All: true})
+	t.Cmp(commits, 3)
+	commits, _ = run("2025-07", stats.RefsSelection{Branches: []string{"feature"}})
+	t.Cmp(commits, 3)
+	t.Cmp(cacheFiles(), 3)
+}
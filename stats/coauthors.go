@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CoAuthorCredit is the way lines edited by a commit are credited to its co-authors
+type CoAuthorCredit int
+
+const (
+	// FullCredit credits the author and each co-author with all the lines of the commit
+	FullCredit CoAuthorCredit = 0
+	// SplitCredit shares the lines of the commit between the author and the co-authors
+	SplitCredit CoAuthorCredit = 1
+	// NoCredit ignores the co-authors: only the commit author is credited
+	NoCredit CoAuthorCredit = 2
+)
+
+// ParseCoAuthorCredit returns the credit matching the `--co-author-credit` parameter value
+func ParseCoAuthorCredit(value string) (CoAuthorCredit, error) {
+	switch value {
+	case "", "full":
+		return FullCredit, nil
+	case "split":
+		return SplitCredit, nil
+	case "none":
+		return NoCredit, nil
+	default:
+		return FullCredit, fmt.Errorf("invalid co-author credit %s, use one of: full, split, none", value)
+	}
+}
+
+// Identity is a name and an email of a commit contributor
+type Identity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+var coAuthorTrailer = regexp.MustCompile(`(?im)^\s*co-authored-by:\s*(.*?)\s*<([^>]+)>\s*$`)
+
+// ParseCoAuthors returns the identities of the `Co-authored-by:` trailers of a commit message
+func ParseCoAuthors(message string) []Identity {
+	var coAuthors []Identity
+	for _, match := range coAuthorTrailer.FindAllStringSubmatch(message, -1) {
+		coAuthors = append(coAuthors, Identity{
+			Name:  match[1],
+			Email: strings.TrimSpace(match[2]),
+		})
+	}
+	return coAuthors
+}
+
+// contributors returns the canonical names of the author and co-authors of the commit,
+// the author first and without duplicates
+func (c *CommitRecord) contributors(mailmap *Mailmap, credit CoAuthorCredit) []string {
+	author, _ := mailmap.Resolve(c.AuthorName, c.AuthorEmail)
+	names := []string{author}
+	if credit == NoCredit {
+		return names
+	}
+	for _, coAuthor := range c.CoAuthors {
+		name, _ := mailmap.Resolve(coAuthor.Name, coAuthor.Email)
+		if !sliceContains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// matchContributor returns true if one of the `users` designates the author of the commit,
+// or one of its co-authors unless they are not credited
+func (c *CommitRecord) matchContributor(users []string, mailmap *Mailmap, credit CoAuthorCredit) bool {
+	if matchUser(users, mailmap, c.AuthorName, c.AuthorEmail) {
+		return true
+	}
+	if credit == NoCredit {
+		return false
+	}
+	for _, coAuthor := range c.CoAuthors {
+		if matchUser(users, mailmap, coAuthor.Name, coAuthor.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestedContributors returns the names of the contributors of the commit designated
+// by one of the `users`, the co-authors only if they are credited
+func (c *CommitRecord) requestedContributors(users []string, mailmap *Mailmap, credit CoAuthorCredit) []string {
+	identities := []Identity{{Name: c.AuthorName, Email: c.AuthorEmail}}
+	if credit != NoCredit {
+		identities = append(identities, c.CoAuthors...)
+	}
+	var names []string
+	for _, identity := range identities {
+		if matchUser(users, mailmap, identity.Name, identity.Email) {
+			name, _ := mailmap.Resolve(identity.Name, identity.Email)
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// creditedLines returns the lines credited to each of the `contributors` of a commit,
+// the remainder of a split goes to the author
+func creditedLines(lines int, contributors int, credit CoAuthorCredit) []int {
+	credited := make([]int, contributors)
+	for i := range credited {
+		credited[i] = lines
+	}
+	if credit == SplitCredit && contributors > 1 {
+		for i := range credited {
+			credited[i] = lines / contributors
+		}
+		credited[0] += lines % contributors
+	}
+	return credited
+}
@@ -0,0 +1,43 @@
+package stats_test
+
+import (
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestParseCoAuthors(tt *testing.T) {
+	t := td.NewT(tt)
+
+	message := `Pair on the parser
+
+Some details mentioning co-authored-by: in the body.
+
+Co-authored-by: Bob Builder <bob@corp.com>
+co-authored-by:Carol <carol@corp.com>
+Signed-off-by: Alice <alice@corp.com>`
+
+	t.Cmp(stats.ParseCoAuthors(message), []stats.Identity{
+		{Name: "Bob Builder", Email: "bob@corp.com"},
+		{Name: "Carol", Email: "carol@corp.com"},
+	})
+	t.Cmp(stats.ParseCoAuthors("Single author commit"), td.Nil())
+}
+
+func TestParseCoAuthorCredit(tt *testing.T) {
+	t := td.NewT(tt)
+
+	for value, expected := range map[string]stats.CoAuthorCredit{
+		"":      stats.FullCredit,
+		"full":  stats.FullCredit,
+		"split": stats.SplitCredit,
+		"none":  stats.NoCredit,
+	} {
+		credit, err := stats.ParseCoAuthorCredit(value)
+		t.CmpNoError(err)
+		t.Cmp(credit, expected)
+	}
+	_, err := stats.ParseCoAuthorCredit("half")
+	t.CmpError(err)
+}
@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// CompareUsers computes the statistics of each of the `users` in the folders over
+// the same scan window, then prints their heatmaps followed by a comparison table
+func CompareUsers(opts LaunchOptions, users []string) error {
+	if len(users) < 2 {
+		return errors.New("give at least two users to compare")
+	}
+	for _, user := range users {
+		if strings.HasPrefix(user, "-") {
+			// the flags given after the first argument are read as users
+			return fmt.Errorf("invalid user %s, flags must come before the users", user)
+		}
+	}
+	opts.Merge = true
+	results := make([]*StatsResult, 0, len(users))
+	for _, user := range users {
+		user := user
+		opts.User = &user
+		userResults, err := analyzeWithProgress(opts)
+		if err != nil {
+			return err
+		}
+		results = append(results, userResults[0])
+	}
+
+	for _, r := range results {
+		if !r.analyzed() {
+			// only listed in the failures
+			continue
+		}
+		fmt.Println()
+		printHeader(r)
+		StatsResultConsolePrinter{Console}.print(r, -1)
+	}
+	fmt.Println()
+	if err := PrintComparison(os.Stdout, users, results); err != nil {
+		return err
+	}
+	// the repositories are the same for all the users
+	return reportFailures(opts, results[:1])
+}
+
+// PrintComparison writes the table comparing the activity of the `users`,
+// one column per user with the statistics of the matching result
+func PrintComparison(w io.Writer, users []string, results []*StatsResult) error {
+	rows := [][]string{
+		{""},
+		{"Commits"},
+		{"Active days"},
+		{"Longest streak"},
+		{"Lines added"},
+		{"Lines removed"},
+		{"Peak hour"},
+		{"Peak weekday"},
+	}
+	for i, r := range results {
+		rows[0] = append(rows[0], users[i])
+		if !r.analyzed() {
+			for row := 1; row < len(rows); row++ {
+				rows[row] = append(rows[row], "error")
+			}
+			continue
+		}
+		a := r.Activity()
+		peakHour, peakWeekday := "-", "-"
+		if a.PeakHour >= 0 {
+			peakHour = fmt.Sprintf("%02d:00", a.PeakHour)
+		}
+		if a.PeakWeekday >= 0 {
+			peakWeekday = r.Options.locale().weekday(a.PeakWeekday)
+		}
+		rows[1] = append(rows[1], fmt.Sprint(a.Commits))
+		rows[2] = append(rows[2], fmt.Sprint(a.ActiveDays))
+		rows[3] = append(rows[3], days(a.LongestStreak))
+		rows[4] = append(rows[4], fmt.Sprintf("+%d", a.Additions))
+		rows[5] = append(rows[5], fmt.Sprintf("-%d", a.Deletions))
+		rows[6] = append(rows[6], peakHour)
+		rows[7] = append(rows[7], peakWeekday)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	return table.Flush()
+}
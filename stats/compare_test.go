@@ -0,0 +1,60 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestCompare(tt *testing.T) {
+	t := td.NewT(tt)
+
+	tuesday := time.Date(2025, time.September, 2, 9, 0, 0, 0, time.UTC)
+	repo := newTestRepository(tt)
+	for day := 0; day < 3; day++ {
+		repo.commit("a.txt", fmt.Sprint(day), "Alice", "alice@corp.com", tuesday.AddDate(0, 0, day), "alice")
+	}
+	repo.commit("a.txt", "a\nb\nc\n", "Alice", "alice@corp.com", tuesday.AddDate(0, 0, 7), "alice")
+	repo.commit("b.txt", "b\n", "Bob", "bob@corp.com", tuesday.AddDate(0, 0, 3).Add(8*time.Hour), "bob")
+
+	users := []string{"alice@corp.com", "Bob", "nobody@corp.com"}
+	var results []*stats.StatsResult
+	for _, user := range users {
+		user := user
+		r, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache: true,
+			User:    &user,
+			Folders: []string{repo.path},
+			Merge:   true,
+			Since:   "2025-09",
+			Until:   "2025-09",
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		results = append(results, r[0])
+	}
+
+	t.Cmp(results[0].Activity(), td.Struct(stats.Activity{
+		Commits:       4,
+		ActiveDays:    4,
+		LongestStreak: 3,
+		Additions:     6,
+		Deletions:     3,
+		PeakHour:      9,
+		PeakWeekday:   time.Tuesday,
+	}, nil))
+	t.Cmp(results[2].Activity(), td.Struct(stats.Activity{PeakHour: -1, PeakWeekday: -1}, nil))
+
+	var out bytes.Buffer
+	t.CmpNoError(stats.PrintComparison(&out, users, results))
+	t.Cmp(out.String(), td.Re(`(?m)^Longest streak +3 days +1 day +0 days$`))
+	t.Cmp(out.String(), td.Re(`(?m)^Peak hour +09:00 +17:00 +-$`))
+	t.Cmp(out.String(), td.Re(`(?m)^Peak weekday +Tu +Fr +-$`))
+
+	err := stats.CompareUsers(stats.LaunchOptions{Folders: []string{tt.TempDir()}}, []string{"alice@corp.com", "bob@corp.com", "--since", "this-year"})
+	t.Cmp(err, td.Contains("flags must come before the users"))
+}
@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the content of the configuration file: default values of the command
+// line flags, identity aliases and named profiles overriding the defaults.
+//
+//	weeks: 26
+//	file-exclude-pattern: [vendor/, "\\.lock$"]
+//	theme: colorblind
+//	aliases:
+//	  me: [me@corp.com, me@personal.org]
+//	profiles:
+//	  work:
+//	    user: me
+//	    group: [backend]
+type Config struct {
+	// Settings holds the default values, keyed by flag name.
+	// The `user` and `folders` settings replace the command arguments.
+	Settings map[string]interface{} `yaml:",inline"`
+	// Aliases holds the identities (emails or names) designated by each alias
+	Aliases map[string][]string `yaml:"aliases"`
+	// Profiles holds the settings of each profile
+	Profiles map[string]map[string]interface{} `yaml:"profiles"`
+}
+
+// GetConfigFilePath returns the default configuration file path
+func GetConfigFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitcontribution", "config.yaml"), nil
+}
+
+// LoadConfig reads the configuration file `filePath`,
+// an empty configuration is returned if the file does not exist and `optional` is true
+func LoadConfig(filePath string, optional bool) (*Config, error) {
+	config := &Config{}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+		return nil, fmt.Errorf("cannot read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filePath, err)
+	}
+	return config, nil
+}
+
+// Values returns the settings of the `profile` merged over the default ones,
+// as lists of strings keyed by flag name
+func (c *Config) Values(profile string) (map[string][]string, error) {
+	values := make(map[string][]string)
+	if err := addConfigValues(values, c.Settings); err != nil {
+		return nil, err
+	}
+	if profile == "" {
+		return values, nil
+	}
+	settings, ok := c.Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %s, use one of: %s", profile, strings.Join(c.ProfileNames(), ", "))
+	}
+	if err := addConfigValues(values, settings); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+	return values, nil
+}
+
+// ProfileNames returns the sorted names of the profiles
+func (c *Config) ProfileNames() []string {
+	var names []string
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExpandAliases replaces the aliases of the comma separated `users` list by their identities
+func (c *Config) ExpandAliases(users string) string {
+	var expanded []string
+	for _, user := range strings.Split(users, ",") {
+		if identities, ok := c.Aliases[strings.TrimSpace(user)]; ok {
+			expanded = joinSlices(identities, expanded)
+		} else {
+			expanded = joinSlices([]string{user}, expanded)
+		}
+	}
+	return strings.Join(expanded, ",")
+}
+
+// ExpandUsers returns the comma separated list of all the `users` with their aliases
+// expanded, nil without users
+func (c *Config) ExpandUsers(users []string) *string {
+	if len(users) == 0 {
+		return nil
+	}
+	expanded := c.ExpandAliases(strings.Join(users, ","))
+	return &expanded
+}
+
+// addConfigValues adds to `values` the `settings` converted to lists of strings,
+// replacing the existing ones
+func addConfigValues(values map[string][]string, settings map[string]interface{}) error {
+	for name, setting := range settings {
+		switch v := setting.(type) {
+		case nil:
+			delete(values, name)
+		case []interface{}:
+			list := []string{}
+			for _, item := range v {
+				if _, ok := item.(map[string]interface{}); ok {
+					return fmt.Errorf("invalid value for setting %s", name)
+				}
+				list = append(list, fmt.Sprint(item))
+			}
+			values[name] = list
+		case map[string]interface{}:
+			return fmt.Errorf("invalid value for setting %s", name)
+		default:
+			values[name] = []string{fmt.Sprint(v)}
+		}
+	}
+	return nil
+}
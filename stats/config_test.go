@@ -0,0 +1,75 @@
+package stats_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestConfig(tt *testing.T) {
+	t := td.NewT(tt)
+
+	path := filepath.Join(tt.TempDir(), "config.yaml")
+	t.CmpNoError(os.WriteFile(path, []byte(`
+weeks: 26
+count-all: true
+file-exclude-pattern: [vendor/, "\\.lock$"]
+aliases:
+  me: [me@corp.com, Me Myself]
+profiles:
+  work:
+    user: me
+    count-all: false
+    group: [backend, frontend]
+  nested:
+    theme:
+      name: github
+`), 0644))
+
+	config, err := stats.LoadConfig(path, false)
+	t.CmpNoError(err)
+	t.Cmp(config.ProfileNames(), []string{"nested", "work"})
+
+	values, err := config.Values("")
+	t.CmpNoError(err)
+	t.Cmp(values, map[string][]string{
+		"weeks":                {"26"},
+		"count-all":            {"true"},
+		"file-exclude-pattern": {"vendor/", `\.lock$`},
+	})
+
+	values, err = config.Values("work")
+	t.CmpNoError(err)
+	t.Cmp(values, map[string][]string{
+		"weeks":                {"26"},
+		"count-all":            {"false"},
+		"file-exclude-pattern": {"vendor/", `\.lock$`},
+		"user":                 {"me"},
+		"group":                {"backend", "frontend"},
+	})
+
+	_, err = config.Values("home")
+	t.Cmp(err, td.Contains("unknown profile home, use one of: nested, work"))
+	_, err = config.Values("nested")
+	t.Cmp(err, td.Contains("invalid value for setting theme"))
+
+	t.Cmp(config.ExpandAliases("me,bob@corp.com"), "me@corp.com,Me Myself,bob@corp.com")
+	t.Cmp(config.ExpandUsers([]string{"me", "bob@corp.com"}), td.Ptr("me@corp.com,Me Myself,bob@corp.com"))
+	t.Nil(config.ExpandUsers(nil))
+
+	missing := filepath.Join(tt.TempDir(), "missing.yaml")
+	config, err = stats.LoadConfig(missing, true)
+	t.CmpNoError(err)
+	values, err = config.Values("")
+	t.CmpNoError(err)
+	t.Empty(values)
+	_, err = stats.LoadConfig(missing, false)
+	t.CmpError(err)
+
+	t.CmpNoError(os.WriteFile(path, []byte("weeks: [26"), 0644))
+	_, err = stats.LoadConfig(path, false)
+	t.Cmp(err, td.Contains("invalid config file"))
+}
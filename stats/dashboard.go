@@ -1,10 +1,9 @@
 package stats
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"math"
-	"sort"
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
@@ -30,93 +29,64 @@ func (c Contributions) Str(color string) string {
 	)
 }
 
-func OpenDashboard(opts LaunchOptions) {
-	folders := opts.Folders
+// OpenDashboard computes the statistics of the folders and shows them in the dashboard
+func OpenDashboard(opts LaunchOptions) error {
+	results, err := analyzeWithProgress(opts)
+	if err != nil {
+		return err
+	}
+	if err := ShowDashboard(results); err != nil {
+		return err
+	}
+	return reportFailures(opts, results)
+}
+
+// ShowDashboard shows the results in a terminal dashboard until the user quits
+func ShowDashboard(rLaunch []*StatsResult) error {
 	results := []string{}
 	width, height, _ := term.GetSize(0)
-	rLaunch := Launch(opts)
-	nbCommits := 0
-	nbAnalyzed := 0
+	summary := Summarize(rLaunch)
+	nbCommits := summary.Commits
+	nbAnalyzed := summary.Analyzed
 
 	var hoursData []float64 = make([]float64, 24)
 	var hoursLabels []string = make([]string, 24)
-	var authors map[string][]float64 = make(map[string][]float64)
 	var daysData []float64 = make([]float64, 7)
+	var daysLabels []string = make([]string, 7)
 	colors := []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white"}
 	var contribs []string
 
-	mergedValues := StatsResult{
-		Options:        rLaunch[0].Options,
-		BeginOfScan:    rLaunch[0].BeginOfScan,
-		EndOfScan:      rLaunch[0].EndOfScan,
-		DurationInDays: rLaunch[0].DurationInDays,
-		Folder:         "",
-		Commits:        make(map[int]int),
-		Error:          nil,
-	}
+	mergedValues := summary.Merged
 
-	var nbErrors int
 	for i := 0; i < 24; i++ {
 		hoursLabels[i] = fmt.Sprintf("%d", i)
 	}
 
-	for _, l := range rLaunch {
-		if l.Error != nil {
-			nbErrors += 1
-			continue
-		}
-		nbAnalyzed += 1
-		commitsByRepo := 0
-		for i, commit := range l.Commits {
-			// calculate week day
-			nbCommits += commit
-			commitsByRepo += commit
-			mergedValues.Commits[i] += commit
-		}
-		if commitsByRepo > 0 {
-			line := fmt.Sprintf("%s: %d", l.Folder, commitsByRepo)
-			results = append(results, line)
-		}
-		for i, v := range l.DayCommits {
-			daysData[(i+6)%7] += float64(v)
-		}
-		for i, v := range l.HoursCommits {
-			hoursData[i] += float64(v)
-		}
+	for _, repository := range summary.Repositories {
+		line := fmt.Sprintf("%s: %d", repository.Folder, repository.Commits)
+		results = append(results, line)
+	}
+	locale := summary.Merged.Options.locale()
+	for i, day := range summary.Merged.Options.WeekStart.Weekdays() {
+		daysData[i] += float64(summary.DayCommits[day])
+		daysLabels[i] = locale.weekday(day)
+	}
+	for i, v := range summary.HoursCommits {
+		hoursData[i] += float64(v)
+	}
 
-		for author, c := range l.AuthorsEditions {
-			if authors[author] == nil {
-				authors[author] = make([]float64, 2)
-			}
-			authors[author][0] += float64(c["additions"])
-			authors[author][1] += float64(c["deletions"])
-		}
+	if summary.Errors == len(rLaunch) {
+		return errors.New("no repository could be analyzed")
 	}
 
 	if nbCommits == 0 {
 		fmt.Println("\nNo commits found to parse")
-		return
-	}
-
-	if nbErrors == len(rLaunch) {
-		panic("Launch has only errors")
+		return nil
 	}
 
 	var allContributions []float64
-	var sAuthors []Contributions
-	for a, c := range authors {
-		additionsAndDeletions := c[0] + c[1]
-		allContributions = append(allContributions, additionsAndDeletions)
-		sAuthors = append(sAuthors, Contributions{
-			Author:    a,
-			Additions: int(c[0]),
-			Deletions: int(c[1]),
-		})
-	}
-	sort.Slice(sAuthors, func(i, j int) bool {
-		return sAuthors[i].Total() > sAuthors[j].Total()
-	})
-	for colorIdx, a := range sAuthors {
+	for colorIdx, a := range summary.Contributors {
+		allContributions = append(allContributions, float64(a.Total()))
 		contribs = append(
 			contribs,
 			a.Str(colors[colorIdx%len(colors)]),
@@ -124,7 +94,7 @@ func OpenDashboard(opts LaunchOptions) {
 	}
 
 	if err := ui.Init(); err != nil {
-		log.Fatalf("failed to initialize termui: %v", err)
+		return fmt.Errorf("failed to initialize termui: %w", err)
 	}
 	defer ui.Close()
 
@@ -135,14 +105,12 @@ func OpenDashboard(opts LaunchOptions) {
 		user = *rLaunch[0].Options.EmailOrUsername
 	}
 
-	analyzed := len(folders)
-	if analyzed != nbAnalyzed {
-		analyzed = nbAnalyzed
-	}
+	analyzed := nbAnalyzed
 
 	p.Rows = []string{
 		fmt.Sprintf("BeginDate: %s", rLaunch[0].BeginOfScan),
 		fmt.Sprintf("EndDate: %s", rLaunch[0].EndOfScan),
+		fmt.Sprintf("Time zone: %s", rLaunch[0].Options.timezoneLabel()),
 		fmt.Sprintf("Commits: %d", nbCommits),
 		fmt.Sprintf("Analyzed repos: %d", analyzed),
 		fmt.Sprintf("User analyzed: %s", user),
@@ -152,15 +120,15 @@ func OpenDashboard(opts LaunchOptions) {
 	bc := widgets.NewBarChart()
 	bc.Title = "Commits on weekday"
 	bc.SetRect(0, height*2/3, width/4, height)
-	bc.Labels = []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}
+	bc.Labels = daysLabels
 	bc.BarGap = 0
 	bc.Data = daysData
 	bc.BarWidth = int(width / 7 / 4)
 
 	hoursGraph := widgets.NewBarChart()
 	hoursGraph.Title = "Commits on daytime"
-	hoursGraph.SetRect(0, height/3, width, height*2/3)
-	hoursGraph.BarWidth = int(width / 12 / 2)
+	hoursGraph.SetRect(0, height/3, width/2, height*2/3)
+	hoursGraph.BarWidth = int(width / 2 / 24)
 	hoursGraph.Data = hoursData
 	hoursGraph.Labels = hoursLabels
 	hoursGraph.BarGap = 0
@@ -180,6 +148,22 @@ func OpenDashboard(opts LaunchOptions) {
 	}
 	contribGraph.SetRect(width/3*2, 0, width, height/3)
 
+	languageNames, languageLines := dashboardLanguages(summary.Languages)
+	languagesGraph := widgets.NewPieChart()
+	languagesGraph.Title = "Languages"
+	languagesGraph.Data = languageLines
+	languagesGraph.Colors = contribGraph.Colors
+	languagesGraph.AngleOffset = -.5 * math.Pi
+	languagesGraph.LabelFormatter = func(i int, v float64) string {
+		return languageNames[i]
+	}
+	languagesGraph.SetRect(width/2, height/3, width/3*2, height*2/3)
+
+	activity := widgets.NewList()
+	activity.Title = "Activity"
+	activity.Rows = mergedValues.Activity().lines(locale)
+	activity.SetRect(width/3*2, height/3, width, height*2/3)
+
 	foldersStats := widgets.NewList()
 	foldersStats.Title = "Repositories"
 	foldersStats.Rows = results
@@ -195,7 +179,7 @@ func OpenDashboard(opts LaunchOptions) {
 	}
 	heatmap.Text = StatsResultConsolePrinter{Dashboard}.print(&mergedValues, defaultDurationTruncated)
 
-	ui.Render(p, bc, hoursGraph, contribGraph, contributors, foldersStats, heatmap)
+	ui.Render(p, bc, hoursGraph, languagesGraph, activity, contribGraph, contributors, foldersStats, heatmap)
 
 	uiEvents := ui.PollEvents()
 	selectedList := contributors
@@ -203,7 +187,7 @@ func OpenDashboard(opts LaunchOptions) {
 	for e := range uiEvents {
 		switch e.ID {
 		case "q", "<C-c>":
-			return
+			return nil
 		case "k", "<Up>":
 			selectedList.ScrollUp()
 		case "j", "<Down>":
@@ -220,4 +204,30 @@ func OpenDashboard(opts LaunchOptions) {
 
 		ui.Render(contributors, foldersStats)
 	}
+	return nil
+}
+
+// dashboardLanguages returns the names and the lines edited of the languages shown
+// by the dashboard, the least edited ones are grouped to keep the chart readable
+func dashboardLanguages(languages Languages) ([]string, []float64) {
+	const shown = 6
+	names := []string{}
+	lines := []float64{}
+	for i, language := range languages.Sorted() {
+		e := languages[language]
+		if e.Lines() == 0 {
+			break
+		}
+		if i < shown {
+			names = append(names, language)
+			lines = append(lines, float64(e.Lines()))
+			continue
+		}
+		if i == shown {
+			names = append(names, "others")
+			lines = append(lines, 0)
+		}
+		lines[shown] += float64(e.Lines())
+	}
+	return names, lines
 }
@@ -0,0 +1,137 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var quarterPattern = regexp.MustCompile(`^(\d{4})-[qQ]([1-4])$`)
+
+// parsePeriod returns the first and last days of the period designated by `value`:
+// a day (2026-07-01), a month (2026-07), a quarter (2026-Q3), a year (2026)
+// or a period relative to `now` (today, yesterday, this-week, last-week, this-month,
+// last-month, this-quarter, last-quarter, this-year, last-year). The weeks begin on `weekStart`.
+func parsePeriod(value string, now time.Time, weekStart WeekStart) (time.Time, time.Time, error) {
+	today := getBeginningOfDay(now)
+	year, month, _ := today.Date()
+	loc := now.Location()
+	weekBegin := weekStart.startOfWeek(today)
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	quarterStart := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc)
+	yearStart := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+
+	var begin, end time.Time
+	switch value {
+	case "today":
+		begin, end = today, today
+	case "yesterday":
+		begin = today.AddDate(0, 0, -1)
+		end = begin
+	case "this-week":
+		begin, end = weekBegin, weekBegin.AddDate(0, 0, 6)
+	case "last-week":
+		begin, end = weekBegin.AddDate(0, 0, -7), weekBegin.AddDate(0, 0, -1)
+	case "this-month":
+		begin, end = monthStart, monthStart.AddDate(0, 1, -1)
+	case "last-month":
+		begin, end = monthStart.AddDate(0, -1, 0), monthStart.AddDate(0, 0, -1)
+	case "this-quarter":
+		begin, end = quarterStart, quarterStart.AddDate(0, 3, -1)
+	case "last-quarter":
+		begin, end = quarterStart.AddDate(0, -3, 0), quarterStart.AddDate(0, 0, -1)
+	case "this-year":
+		begin, end = yearStart, yearStart.AddDate(1, 0, -1)
+	case "last-year":
+		begin, end = yearStart.AddDate(-1, 0, 0), yearStart.AddDate(0, 0, -1)
+	default:
+		if match := quarterPattern.FindStringSubmatch(value); match != nil {
+			y, _ := strconv.Atoi(match[1])
+			q, _ := strconv.Atoi(match[2])
+			begin = time.Date(y, time.Month(3*q-2), 1, 0, 0, 0, 0, loc)
+			return begin, begin.AddDate(0, 3, -1), nil
+		}
+		if date, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+			return date, date, nil
+		}
+		if date, err := time.ParseInLocation("2006-01", value, loc); err == nil {
+			return date, date.AddDate(0, 1, -1), nil
+		}
+		if date, err := time.ParseInLocation("2006", value, loc); err == nil {
+			return date, date.AddDate(1, 0, -1), nil
+		}
+		return begin, end, fmt.Errorf("invalid date %s, use YYYY-MM-DD, YYYY-MM, YYYY-Qn, YYYY or a relative period like last-quarter", value)
+	}
+	return begin, end, nil
+}
+
+// populateDateRange sets the scan window from the `--since` and `--until` values:
+// from the first day of the `since` period to the last day of the `until` period.
+// A missing bound is computed from the other one and the number of weeks, or now.
+// An error is returned on an invalid period or an inverted window.
+func populateDateRange(options LaunchOptions, r *StatsResult) error {
+	if options.Delta != "" {
+		return fmt.Errorf("delta cannot be used with since or until")
+	}
+	now := scanNow(options.Timezone)
+	end := getBeginningOfDay(now)
+	if options.Until != "" {
+		_, until, err := parsePeriod(options.Until, now, options.WeekStart)
+		if err != nil {
+			return err
+		}
+		end = until
+	}
+
+	durationInDays := DefaultDurationInDays
+	if options.DurationInWeeks > 0 {
+		durationInDays = options.DurationInWeeks * 7
+	}
+	begin := end.AddDate(0, 0, 1-durationInDays)
+	if options.Since != "" {
+		since, _, err := parsePeriod(options.Since, now, options.WeekStart)
+		if err != nil {
+			return err
+		}
+		begin = since
+	}
+	if begin.After(end) {
+		return fmt.Errorf("since %s is after until %s", begin.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	r.BeginOfScan = getBeginningOfDay(begin)
+	r.EndOfScan = getEndOfDay(end)
+	r.DurationInDays = r.scanDays()
+	return nil
+}
+
+// DateSource is the commit timestamp used for the scan window and the statistics
+type DateSource int
+
+const (
+	// AuthorDate uses the date the commit was originally written
+	AuthorDate DateSource = 0
+	// CommitterDate uses the date the commit was last applied, by a rebase or a cherry-pick
+	CommitterDate DateSource = 1
+)
+
+// ParseDateSource returns the date source matching the `--date-source` parameter value
+func ParseDateSource(value string) (DateSource, error) {
+	switch value {
+	case "", "author":
+		return AuthorDate, nil
+	case "committer":
+		return CommitterDate, nil
+	default:
+		return AuthorDate, fmt.Errorf("invalid date source %s, use one of: author, committer", value)
+	}
+}
+
+// date returns the timestamp of the commit selected by `source`
+func (c *CommitRecord) date(source DateSource) time.Time {
+	if source == CommitterDate {
+		return c.CommitterWhen
+	}
+	return c.AuthorWhen
+}
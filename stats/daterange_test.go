@@ -0,0 +1,131 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestScanDateRange(tt *testing.T) {
+	t := td.NewT(tt)
+
+	repo := newTestRepository(tt)
+	for _, day := range []string{"2025-06-30", "2025-07-01", "2025-08-15", "2025-09-30", "2025-10-01"} {
+		when, _ := time.ParseInLocation("2006-01-02 15:04", day+" 10:00", time.Local)
+		repo.commit(day+".txt", day, "Alice", "alice@corp.com", when, day)
+	}
+
+	analyze := func(since string, until string) ([]*stats.StatsResult, error) {
+		return stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache: true,
+			Folders: []string{repo.path, repo.path},
+			Since:   since,
+			Until:   until,
+		}).Run(context.Background())
+	}
+	run := func(since string, until string) *stats.StatsResult {
+		results, err := analyze(since, until)
+		t.CmpNoError(err)
+		return results[0]
+	}
+	date := func(value string) time.Time {
+		d, _ := time.ParseInLocation("2006-01-02", value, time.Local)
+		return d
+	}
+
+	for _, r := range []*stats.StatsResult{run("2025-07-01", "2025-09-30"), run("2025-Q3", "2025-q3"), run("2025-07", "2025-09")} {
+		t.CmpNoError(r.Error)
+		t.Cmp(r.BeginOfScan, date("2025-07-01"))
+		t.Cmp(r.EndOfScan, date("2025-09-30").Add(24*time.Hour-time.Second))
+		t.Cmp(r.DurationInDays, 92)
+		t.Cmp(r.CommitsOn(date("2025-07-01")), 1, "first day of the window")
+		t.Cmp(r.CommitsOn(date("2025-09-30")), 1, "last day of the window")
+		t.Cmp(r.CommitsOn(date("2025-06-30")), 0)
+
+		total := 0
+		for _, c := range r.Commits {
+			total += c
+		}
+		t.Cmp(total, 3)
+		t.Cmp(r.DayCommits[time.Tuesday]+r.DayCommits[time.Friday], 3)
+	}
+
+	r := run("2025", "2025")
+	t.CmpNoError(r.Error)
+	t.Cmp(r.DurationInDays, 365)
+
+	t.CmpNoError(run("last-quarter", "last-quarter").Error)
+
+	for _, weekStart := range []stats.WeekStart{stats.MondayStart, stats.SundayStart, stats.SaturdayStart} {
+		for _, period := range []string{"this-week", "last-week"} {
+			results, err := stats.NewAnalyzer(stats.LaunchOptions{
+				NoCache:   true,
+				Folders:   []string{repo.path},
+				Since:     period,
+				Until:     period,
+				WeekStart: weekStart,
+			}).Run(context.Background())
+			t.CmpNoError(err)
+			t.Cmp(results[0].BeginOfScan.Weekday(), weekStart.Weekday(), period)
+			t.Cmp(results[0].DurationInDays, 7, period)
+		}
+	}
+
+	// an invalid window is an invalid option, not a failure of each repository
+	results, err := analyze("2025-Q5", "")
+	t.Cmp(err, td.Contains("invalid date 2025-Q5"))
+	t.Nil(results)
+	_, err = analyze("2025-10-01", "2025-09-30")
+	t.Cmp(err, td.Contains("is after until"))
+	_, err = stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		Folders: []string{repo.path},
+		Since:   "2025-07",
+		Delta:   "1w",
+	}).Run(context.Background())
+	t.Cmp(err, td.String("delta cannot be used with since or until"))
+}
+
+func TestDateSource(tt *testing.T) {
+	t := td.NewT(tt)
+
+	written := time.Date(2025, time.May, 20, 10, 0, 0, 0, time.Local)
+	rebased := time.Date(2025, time.July, 2, 15, 0, 0, 0, time.Local)
+	repo := newTestRepository(tt)
+	repo.commitAs("a.txt", "a",
+		&object.Signature{Name: "Alice", Email: "alice@corp.com", When: written},
+		&object.Signature{Name: "Bob", Email: "bob@corp.com", When: rebased},
+		"rebased by Bob")
+
+	run := func(source stats.DateSource, user string, matchCommitter bool) *stats.StatsResult {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache:        true,
+			User:           &user,
+			Folders:        []string{repo.path},
+			Since:          "2025-07",
+			Until:          "2025-07",
+			DateSource:     source,
+			MatchCommitter: matchCommitter,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+		return results[0]
+	}
+
+	t.Cmp(run(stats.AuthorDate, "alice@corp.com", false).HoursCommits[10], 0, "written before the window")
+	r := run(stats.CommitterDate, "alice@corp.com", false)
+	t.Cmp(r.CommitsOn(rebased), 1)
+	t.Cmp(r.HoursCommits[15], 1)
+
+	t.Cmp(run(stats.CommitterDate, "bob@corp.com", false).HoursCommits[15], 0)
+	r = run(stats.CommitterDate, "bob@corp.com", true)
+	t.Cmp(r.HoursCommits[15], 1)
+	t.Cmp(r.AuthorsEditions, td.ContainsKey("Alice"), "lines are credited to the author")
+
+	_, err := stats.ParseDateSource("tagger")
+	t.CmpError(err)
+}
@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotRepository is returned when a scanned folder is not a git repository
+var ErrNotRepository = errors.New("not a repository")
+
+// RepositoryError is the error stopping the analysis of one repository,
+// the other repositories are still analyzed
+type RepositoryError struct {
+	Path string
+	Err  error
+}
+
+func (e *RepositoryError) Error() string {
+	return fmt.Sprintf("error scanning folder repository %s: %s", e.Path, e.Err)
+}
+
+func (e *RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// Failures returns the errors of the repositories which could not be analyzed,
+// the error of a result not related to a repository is reported on its folder
+func Failures(results []*StatsResult) []*RepositoryError {
+	failures := []*RepositoryError{}
+	for _, r := range results {
+		if r.Error == nil {
+			continue
+		}
+		found := repositoryErrors(r.Error)
+		if len(found) == 0 {
+			found = append(found, &RepositoryError{Path: r.Folder, Err: r.Error})
+		}
+		failures = append(failures, found...)
+	}
+	return failures
+}
+
+// repositoryErrors returns the repository errors wrapped or joined in `err`
+func repositoryErrors(err error) []*RepositoryError {
+	var found []*RepositoryError
+	switch e := err.(type) {
+	case *RepositoryError:
+		return append(found, e)
+	case interface{ Unwrap() []error }:
+		for _, joined := range e.Unwrap() {
+			found = append(found, repositoryErrors(joined)...)
+		}
+	case interface{ Unwrap() error }:
+		if wrapped := e.Unwrap(); wrapped != nil {
+			found = repositoryErrors(wrapped)
+		}
+	}
+	return found
+}
+
+// analyzed returns true if at least one of the folders of the result could be analyzed
+func (r *StatsResult) analyzed() bool {
+	return r.Commits != nil && len(Failures([]*StatsResult{r})) < len(r.Options.Folders)
+}
+
+// PrintFailures prints the summary of the repositories which could not be analyzed
+func PrintFailures(w io.Writer, failures []*RepositoryError) {
+	if len(failures) == 0 {
+		return
+	}
+	repositories := "repositories"
+	if len(failures) == 1 {
+		repositories = "repository"
+	}
+	fmt.Fprint(w, colorize(Error, fmt.Sprintf("\n%d %s could not be analyzed:\n", len(failures), repositories), Console))
+	for _, failure := range failures {
+		fmt.Fprintf(w, "- %s: %s\n", failure.Path, failure.Err)
+	}
+}
+
+// CheckFailures returns an error if some repositories could not be analyzed
+func CheckFailures(results []*StatsResult) error {
+	if failures := Failures(results); len(failures) > 0 {
+		return fmt.Errorf("%d of the scanned repositories could not be analyzed", len(failures))
+	}
+	return nil
+}
+
+// reportFailures prints the failures summary on the standard error, and returns
+// an error if some repositories could not be analyzed and the run must fail
+func reportFailures(opts LaunchOptions, results []*StatsResult) error {
+	PrintFailures(os.Stderr, Failures(results))
+	if opts.FailOnError {
+		return CheckFailures(results)
+	}
+	return nil
+}
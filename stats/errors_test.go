@@ -0,0 +1,74 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestFailures(tt *testing.T) {
+	t := td.NewT(tt)
+
+	missing := filepath.Join(tt.TempDir(), "missing")
+	folders := append(currentRepo, missing, tt.TempDir())
+
+	for _, merge := range []bool{false, true} {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache:         true,
+			DurationInWeeks: 4,
+			Folders:         folders,
+			Merge:           merge,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+
+		failures := stats.Failures(results)
+		t.Cmp(failures, td.Len(2), "merge %t", merge)
+		t.Cmp(failures[0].Path, missing)
+		t.True(errors.Is(failures[0], stats.ErrNotRepository))
+		t.True(errors.Is(failures[1], stats.ErrNotRepository))
+		t.Cmp(stats.CheckFailures(results), td.Contains("2 of the scanned repositories"))
+
+		var out bytes.Buffer
+		stats.PrintFailures(&out, failures)
+		t.Cmp(out.String(), td.Contains("2 repositories could not be analyzed"))
+		t.Cmp(out.String(), td.Contains("- "+missing+": not a repository\n"))
+	}
+
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{DurationInWeeks: 4, Folders: currentRepo, NoCache: true}).Run(context.Background())
+	t.CmpNoError(err)
+	t.Empty(stats.Failures(results))
+	t.CmpNoError(stats.CheckFailures(results))
+}
+
+func TestPrintStatsFailures(tt *testing.T) {
+	t := td.NewT(tt)
+
+	options := stats.LaunchOptions{
+		NoCache:         true,
+		DurationInWeeks: 4,
+		Folders:         append(currentRepo, tt.TempDir()),
+		Output:          stats.JSON,
+	}
+	t.CmpNoError(stats.PrintStats(options))
+
+	options.FailOnError = true
+	t.Cmp(stats.PrintStats(options), td.Contains("1 of the scanned repositories"))
+
+	options.Since = "yesterday-ish"
+	t.Cmp(stats.PrintStats(options), td.Contains("invalid date yesterday-ish"))
+}
+
+func TestScanErrors(tt *testing.T) {
+	t := td.NewT(tt)
+
+	_, err := stats.ScanGitFolders([]string{}, filepath.Join(tt.TempDir(), "missing"))
+	t.Cmp(err, td.Contains("cannot scan folder"))
+
+	_, err = stats.LoadRegistry(filepath.Join(tt.TempDir(), "missing", ".gogitstats"))
+	t.Cmp(err, td.Contains("cannot open file"))
+}
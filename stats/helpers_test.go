@@ -0,0 +1,104 @@
+package stats_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// testRepository is a git repository created in a temporary folder
+type testRepository struct {
+	t    *testing.T
+	path string
+	repo *git.Repository
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	path := t.TempDir()
+	repo, err := git.PlainInit(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testRepository{t: t, path: path, repo: repo}
+}
+
+// commit writes `content` in `file` and commits it with the author `name <email>` at `when`
+func (r *testRepository) commit(file string, content string, name string, email string, when time.Time, message string) plumbing.Hash {
+	r.t.Helper()
+	signature := &object.Signature{Name: name, Email: email, When: when}
+	return r.commitAs(file, content, signature, signature, message)
+}
+
+// commitAs writes `content` in `file` and commits it with distinct author and committer,
+// the commit has the given parents if any, the HEAD commit otherwise
+func (r *testRepository) commitAs(file string, content string, author *object.Signature, committer *object.Signature, message string, parents ...plumbing.Hash) plumbing.Hash {
+	r.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(r.path, file)), 0755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(r.path, file), []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	if _, err := wt.Add(file); err != nil {
+		r.t.Fatal(err)
+	}
+	hash, err := wt.Commit(message, &git.CommitOptions{Author: author, Committer: committer, Parents: parents})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return hash
+}
+
+// checkout switches to `branch`, creating it if `create` is true
+func (r *testRepository) checkout(branch string, create bool) {
+	r.t.Helper()
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	err = wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: create})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// reset moves the current branch and the worktree to the commit `hash`
+func (r *testRepository) reset(hash plumbing.Hash) {
+	r.t.Helper()
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: hash, Mode: git.HardReset}); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// captureStdout returns what `print` writes on the standard output
+func captureStdout(t *testing.T, print func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+	print()
+	writer.Close()
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
@@ -0,0 +1,140 @@
+package stats
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"time"
+)
+
+//go:embed report.html
+var reportTemplateContent string
+
+var reportTemplate = template.Must(template.New("report").Parse(reportTemplateContent))
+
+// htmlBar is a bar of the report bar charts
+type htmlBar struct {
+	Label   string
+	Value   int
+	Percent float64
+}
+
+// htmlLanguage is a row of the report languages table
+type htmlLanguage struct {
+	Name string
+	LanguageEditions
+	Share float64
+}
+
+// htmlReport holds the data rendered by the report template
+type htmlReport struct {
+	User         string
+	BeginOfScan  string
+	EndOfScan    string
+	GeneratedAt  string
+	Summary      Summary
+	Heatmap      template.HTML
+	Weekdays     []htmlBar
+	Hours        []htmlBar
+	Activity     []string
+	Languages    []htmlLanguage
+	Repositories []RepositoryCommits
+}
+
+// ReportHTML computes the statistics of all the folders and writes in the file
+// `filePath` a standalone HTML report with the same panels as the dashboard
+func ReportHTML(opts LaunchOptions, filePath string) error {
+	opts.Merge = false
+	results, err := analyzeWithProgress(opts)
+	if err != nil {
+		return err
+	}
+	summary := Summarize(results)
+	if len(results) == 0 || summary.Errors == len(results) {
+		PrintFailures(os.Stderr, Failures(results))
+		return errors.New("no repository could be analyzed")
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := WriteHTML(f, summary); err != nil {
+		return err
+	}
+	fmt.Printf("\nReport exported to %s\n", filePath)
+	return reportFailures(opts, results)
+}
+
+// WriteHTML writes the summary as a single HTML page, without any external resource
+func WriteHTML(w io.Writer, summary Summary) error {
+	var heatmap bytes.Buffer
+	if err := WriteSVG(&heatmap, &summary.Merged); err != nil {
+		return err
+	}
+
+	user := "all"
+	if summary.Merged.Options.EmailOrUsername != nil {
+		user = *summary.Merged.Options.EmailOrUsername
+	}
+	report := htmlReport{
+		User:         user,
+		BeginOfScan:  getBeginningOfDay(summary.Merged.BeginOfScan).Format("January 02, 2006"),
+		EndOfScan:    getEndOfDay(summary.Merged.EndOfScan).Format("January 02, 2006"),
+		GeneratedAt:  time.Now().Format("January 02, 2006 15:04:05"),
+		Summary:      summary,
+		Heatmap:      template.HTML(heatmap.String()),
+		Repositories: summary.Repositories,
+	}
+
+	weekdays := make([]int, 7)
+	weekdaysLabels := make([]string, 7)
+	locale := summary.Merged.Options.locale()
+	for i, day := range summary.Merged.Options.WeekStart.Weekdays() {
+		weekdays[i] = summary.DayCommits[day]
+		weekdaysLabels[i] = locale.weekday(day)
+	}
+	report.Weekdays = htmlBars(weekdaysLabels, weekdays)
+
+	hoursLabels := make([]string, 24)
+	for i := range hoursLabels {
+		hoursLabels[i] = fmt.Sprintf("%d", i)
+	}
+	report.Hours = htmlBars(hoursLabels, summary.HoursCommits[:])
+	report.Activity = summary.Merged.Activity().lines(locale)
+
+	total := summary.Languages.lines()
+	for _, language := range summary.Languages.Sorted() {
+		row := htmlLanguage{Name: language, LanguageEditions: summary.Languages[language]}
+		if total > 0 {
+			row.Share = float64(row.Lines()) * 100 / float64(total)
+		}
+		report.Languages = append(report.Languages, row)
+	}
+
+	return reportTemplate.Execute(w, report)
+}
+
+// htmlBars returns the bars of a chart, sized relatively to the highest value
+func htmlBars(labels []string, values []int) []htmlBar {
+	highest := 0
+	for _, v := range values {
+		if v > highest {
+			highest = v
+		}
+	}
+	bars := make([]htmlBar, 0, len(values))
+	for i, v := range values {
+		bar := htmlBar{Label: labels[i], Value: v}
+		if highest > 0 {
+			bar.Percent = float64(v) * 100 / float64(highest)
+		}
+		bars = append(bars, bar)
+	}
+	return bars
+}
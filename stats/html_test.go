@@ -0,0 +1,39 @@
+package stats_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestWriteHTML(tt *testing.T) {
+	t := td.NewT(tt)
+
+	results := stats.Launch(stats.LaunchOptions{
+		NoCache:         true,
+		User:            nil,
+		DurationInWeeks: 4,
+		Folders:         append(currentRepo, currentRepo...),
+		Dashboard:       true,
+	})
+	summary := stats.Summarize(results)
+	t.Cmp(summary.Analyzed, 2)
+	t.Cmp(summary.Errors, 0)
+	t.Cmp(summary.Commits, 2*summary.Repositories[0].Commits)
+
+	var out bytes.Buffer
+	t.CmpNoError(stats.WriteHTML(&out, summary))
+	t.Cmp(out.String(), td.All(
+		td.HasPrefix("<!DOCTYPE html>"),
+		td.Contains("<svg"),
+		td.Contains("Commits on weekday"),
+		td.Contains("Commits on daytime"),
+		td.Contains("<h2>Activity</h2>"),
+		td.Re(`<li>(Commits: \d+ on |No commits)`),
+		td.Contains("Contributors"),
+		td.Contains("Repositories"),
+		td.Not(td.Contains("http://cdn")),
+	))
+}
@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JSONSchemaVersion is the version of the JSON output format.
+// It is incremented on each breaking change of the format, new fields can be added
+// without changing the version.
+const JSONSchemaVersion = 1
+
+const jsonDateFormat = "2006-01-02"
+
+// JSONReport is the document printed with the `--output json` option.
+type JSONReport struct {
+	// SchemaVersion is the JSONSchemaVersion used to build the document
+	SchemaVersion int `json:"schemaVersion"`
+	// GeneratedAt is the date of the report (RFC 3339)
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Results holds one entry per scanned repository, or a single entry when merged
+	Results []JSONResult `json:"results"`
+}
+
+// JSONResult is the JSON representation of a StatsResult.
+type JSONResult struct {
+	// Folder is the comma separated list of the scanned repositories
+	Folder string `json:"folder"`
+	// Folders is the list of the scanned repositories
+	Folders []string `json:"folders"`
+	// User is the filter on authors emails or names, null when all users are counted
+	User *string `json:"user"`
+	// BeginOfScan and EndOfScan are the scan window bounds (RFC 3339)
+	BeginOfScan time.Time `json:"beginOfScan"`
+	EndOfScan   time.Time `json:"endOfScan"`
+	// Timezone is the time zone of the days and hours: an IANA name, local or author
+	Timezone string `json:"timezone"`
+	// TotalCommits is the number of commits in the scan window
+	TotalCommits int `json:"totalCommits"`
+	// Days holds the number of commits of each day of the scan window, in chronological order
+	Days []JSONDay `json:"days"`
+	// HoursCommits holds the number of commits per hour of the day, index 0 is midnight
+	HoursCommits [24]int `json:"hoursCommits"`
+	// WeekdayCommits holds the number of commits per day of the week, index 0 is Sunday
+	WeekdayCommits [7]int `json:"weekdayCommits"`
+	// Authors holds the lines edited per author, sorted by decreasing additions + deletions
+	Authors []JSONAuthor `json:"authors"`
+	// Error is the error message of the scan, null on success
+	Error *string `json:"error"`
+}
+
+// JSONDay is the number of commits on a day.
+type JSONDay struct {
+	// Date uses the YYYY-MM-DD format
+	Date    string `json:"date"`
+	Commits int    `json:"commits"`
+}
+
+// JSONAuthor is the number of lines edited by an author.
+type JSONAuthor struct {
+	Name      string `json:"name"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// NewJSONReport builds the JSON report of the results.
+func NewJSONReport(results []*StatsResult) JSONReport {
+	report := JSONReport{
+		SchemaVersion: JSONSchemaVersion,
+		GeneratedAt:   time.Now(),
+		Results:       make([]JSONResult, 0, len(results)),
+	}
+	for _, r := range results {
+		report.Results = append(report.Results, newJSONResult(r))
+	}
+	return report
+}
+
+func newJSONResult(r *StatsResult) JSONResult {
+	out := JSONResult{
+		Folder:         strings.Join(r.Options.Folders, ","),
+		Folders:        r.Options.Folders,
+		User:           r.Options.EmailOrUsername,
+		BeginOfScan:    r.BeginOfScan,
+		EndOfScan:      r.EndOfScan,
+		Timezone:       r.Options.Timezone,
+		HoursCommits:   r.HoursCommits,
+		WeekdayCommits: r.DayCommits,
+		Days:           []JSONDay{},
+		Authors:        []JSONAuthor{},
+	}
+	if r.Error != nil {
+		message := r.Error.Error()
+		out.Error = &message
+	}
+	if !r.analyzed() {
+		// nothing could be analyzed
+		return out
+	}
+
+	for day := getBeginningOfDay(r.BeginOfScan); day.Before(r.EndOfScan); day = day.AddDate(0, 0, 1) {
+		commits := r.CommitsOn(day)
+		out.TotalCommits += commits
+		out.Days = append(out.Days, JSONDay{
+			Date:    day.Format(jsonDateFormat),
+			Commits: commits,
+		})
+	}
+
+	for author, editions := range r.AuthorsEditions {
+		out.Authors = append(out.Authors, JSONAuthor{
+			Name:      author,
+			Additions: editions["additions"],
+			Deletions: editions["deletions"],
+		})
+	}
+	sort.Slice(out.Authors, func(i, j int) bool {
+		ti := out.Authors[i].Additions + out.Authors[i].Deletions
+		tj := out.Authors[j].Additions + out.Authors[j].Deletions
+		if ti != tj {
+			return ti > tj
+		}
+		return out.Authors[i].Name < out.Authors[j].Name
+	})
+	return out
+}
+
+// PrintJSON writes the JSON report of the results to `w`.
+func PrintJSON(w io.Writer, results []*StatsResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewJSONReport(results))
+}
@@ -0,0 +1,82 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestJSONReport(tt *testing.T) {
+	t := td.NewT(tt)
+
+	results := stats.Launch(stats.LaunchOptions{
+		NoCache:         true,
+		User:            nil,
+		DurationInWeeks: 4,
+		Folders:         currentRepo,
+		Dashboard:       true,
+	})
+	t.CmpNoError(results[0].Error)
+	results = append(results, &stats.StatsResult{
+		Options: stats.StatsOptions{Folders: []string{"missing"}},
+		Error:   errors.New("cannot get stat from folder (not a repository): missing"),
+	})
+
+	var out bytes.Buffer
+	t.CmpNoError(stats.PrintJSON(&out, results))
+
+	var report stats.JSONReport
+	t.CmpNoError(json.Unmarshal(out.Bytes(), &report))
+	t.Cmp(report.SchemaVersion, stats.JSONSchemaVersion)
+	t.Cmp(report.Results, td.Len(2))
+
+	r := report.Results[0]
+	t.Cmp(r.Error, td.Nil())
+	t.Cmp(r.Days, td.Len(td.Gte(28)))
+	t.Cmp(r.Days[0].Date, results[0].BeginOfScan.Format("2006-01-02"))
+	total := 0
+	for _, day := range r.Days {
+		total += day.Commits
+	}
+	t.Cmp(r.TotalCommits, total)
+
+	t.Cmp(report.Results[1].Folder, "missing")
+	t.Cmp(report.Results[1].Error, td.Ptr(td.Contains("not a repository")))
+}
+
+func TestJSONReportPartialMerge(tt *testing.T) {
+	t := td.NewT(tt)
+
+	monday := time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC)
+	repo := newTestRepository(tt)
+	repo.commit("a.txt", "a\n", "Alice", "alice@corp.com", monday, "first")
+	repo.commit("a.txt", "b\n", "Alice", "alice@corp.com", monday.AddDate(0, 0, 1), "second")
+
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		Folders: []string{repo.path, tt.TempDir()},
+		Merge:   true,
+		Since:   "2025-02",
+		Until:   "2025-02",
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	t.CmpError(results[0].Error)
+
+	var out bytes.Buffer
+	t.CmpNoError(stats.PrintJSON(&out, results))
+	var report stats.JSONReport
+	t.CmpNoError(json.Unmarshal(out.Bytes(), &report))
+
+	// the commits of the valid repository are kept next to the error
+	r := report.Results[0]
+	t.Cmp(r.Error, td.Ptr(td.Contains("not a repository")))
+	t.Cmp(r.TotalCommits, 2)
+	t.Cmp(r.Days, td.Len(28))
+	t.Cmp(r.Authors, []stats.JSONAuthor{{Name: "Alice", Additions: 2, Deletions: 1}})
+}
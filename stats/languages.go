@@ -0,0 +1,258 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// otherLanguage is the language of the files not recognized
+const otherLanguage = "Other"
+
+// languagesByFilename holds the languages of the well-known files without extension
+var languagesByFilename = map[string]string{
+	"dockerfile":     "Dockerfile",
+	"containerfile":  "Dockerfile",
+	"makefile":       "Makefile",
+	"gnumakefile":    "Makefile",
+	"cmakelists.txt": "CMake",
+	"jenkinsfile":    "Groovy",
+	"rakefile":       "Ruby",
+	"gemfile":        "Ruby",
+	"vagrantfile":    "Ruby",
+	"go.mod":         "Go",
+	"go.sum":         "Go",
+}
+
+// languagesByExtension holds the languages of the files by lower case extension
+var languagesByExtension = map[string]string{
+	".go":       "Go",
+	".js":       "JavaScript",
+	".mjs":      "JavaScript",
+	".cjs":      "JavaScript",
+	".jsx":      "JavaScript",
+	".ts":       "TypeScript",
+	".mts":      "TypeScript",
+	".tsx":      "TypeScript",
+	".vue":      "Vue",
+	".svelte":   "Svelte",
+	".html":     "HTML",
+	".htm":      "HTML",
+	".css":      "CSS",
+	".scss":     "SCSS",
+	".sass":     "SCSS",
+	".less":     "Less",
+	".py":       "Python",
+	".rb":       "Ruby",
+	".java":     "Java",
+	".kt":       "Kotlin",
+	".kts":      "Kotlin",
+	".scala":    "Scala",
+	".groovy":   "Groovy",
+	".gradle":   "Groovy",
+	".rs":       "Rust",
+	".c":        "C",
+	".h":        "C",
+	".cc":       "C++",
+	".cpp":      "C++",
+	".cxx":      "C++",
+	".hh":       "C++",
+	".hpp":      "C++",
+	".cs":       "C#",
+	".fs":       "F#",
+	".php":      "PHP",
+	".swift":    "Swift",
+	".m":        "Objective-C",
+	".dart":     "Dart",
+	".ex":       "Elixir",
+	".exs":      "Elixir",
+	".erl":      "Erlang",
+	".hs":       "Haskell",
+	".clj":      "Clojure",
+	".lua":      "Lua",
+	".pl":       "Perl",
+	".r":        "R",
+	".sh":       "Shell",
+	".bash":     "Shell",
+	".zsh":      "Shell",
+	".ps1":      "PowerShell",
+	".sql":      "SQL",
+	".proto":    "Protocol Buffers",
+	".tf":       "HCL",
+	".hcl":      "HCL",
+	".cmake":    "CMake",
+	".mk":       "Makefile",
+	".json":     "JSON",
+	".yml":      "YAML",
+	".yaml":     "YAML",
+	".toml":     "TOML",
+	".xml":      "XML",
+	".md":       "Markdown",
+	".markdown": "Markdown",
+	".rst":      "reStructuredText",
+	".txt":      "Text",
+}
+
+// Language returns the language of the file `name`, from its well-known filename
+// or its extension, `Other` if not recognized
+func Language(name string) string {
+	base := strings.ToLower(path.Base(name))
+	if language, ok := languagesByFilename[base]; ok {
+		return language
+	}
+	if strings.HasPrefix(base, "dockerfile.") || strings.HasSuffix(base, ".dockerfile") {
+		return "Dockerfile"
+	}
+	if language, ok := languagesByExtension[path.Ext(base)]; ok {
+		return language
+	}
+	return otherLanguage
+}
+
+// LanguageEditions is the number of commits and lines edited in the files of a language
+type LanguageEditions struct {
+	Commits   int
+	Additions int
+	Deletions int
+}
+
+// Lines returns the number of lines added and deleted
+func (e LanguageEditions) Lines() int {
+	return e.Additions + e.Deletions
+}
+
+// Languages holds the editions per language
+type Languages map[string]LanguageEditions
+
+// addFile adds the lines of a file of a commit, the commits are counted by addCommit
+func (l Languages) addFile(name string, additions int, deletions int) {
+	language := Language(name)
+	e := l[language]
+	e.Additions += additions
+	e.Deletions += deletions
+	l[language] = e
+}
+
+// addCommit adds the languages edited by a commit, counting one commit per language
+func (l Languages) addCommit(commit Languages) {
+	for language, edited := range commit {
+		e := l[language]
+		e.Commits++
+		e.Additions += edited.Additions
+		e.Deletions += edited.Deletions
+		l[language] = e
+	}
+}
+
+// add adds the editions of `other`, as counted in another result
+func (l Languages) add(other Languages) {
+	for language, edited := range other {
+		e := l[language]
+		e.Commits += edited.Commits
+		e.Additions += edited.Additions
+		e.Deletions += edited.Deletions
+		l[language] = e
+	}
+}
+
+// credit returns the languages of a commit credited to each of the `contributors`
+func (l Languages) credit(contributors int, credit CoAuthorCredit) []Languages {
+	credited := make([]Languages, contributors)
+	for i := range credited {
+		credited[i] = make(Languages, len(l))
+	}
+	for language, e := range l {
+		additions := creditedLines(e.Additions, contributors, credit)
+		deletions := creditedLines(e.Deletions, contributors, credit)
+		for i := range credited {
+			credited[i][language] = LanguageEditions{Additions: additions[i], Deletions: deletions[i]}
+		}
+	}
+	return credited
+}
+
+// Sorted returns the languages sorted by decreasing number of lines edited,
+// then by decreasing number of commits and by name
+func (l Languages) Sorted() []string {
+	languages := make([]string, 0, len(l))
+	for language := range l {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		ei, ej := l[languages[i]], l[languages[j]]
+		if ei.Lines() != ej.Lines() {
+			return ei.Lines() > ej.Lines()
+		}
+		if ei.Commits != ej.Commits {
+			return ei.Commits > ej.Commits
+		}
+		return languages[i] < languages[j]
+	})
+	return languages
+}
+
+// lines returns the total number of lines edited in all the languages
+func (l Languages) lines() int {
+	lines := 0
+	for _, e := range l {
+		lines += e.Lines()
+	}
+	return lines
+}
+
+// PrintLanguages writes the commits and lines per language of the result, then per
+// author, only the `top` most active ones if positive, and per repository when several
+func PrintLanguages(w io.Writer, r *StatsResult, top int) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Language\tCommits\tAdded\tDeleted\tShare")
+	total := r.Languages.lines()
+	for _, language := range r.Languages.Sorted() {
+		e := r.Languages[language]
+		share := 0.0
+		if total > 0 {
+			share = float64(e.Lines()) * 100 / float64(total)
+		}
+		fmt.Fprintf(table, "%s\t%d\t+%d\t-%d\t%.0f%%\n", language, e.Commits, e.Additions, e.Deletions, share)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Author\tLanguage\tCommits\tAdded\tDeleted")
+	for _, a := range r.Authors(top) {
+		printLanguagesRows(table, a.Author, r.AuthorsLanguages[a.Author])
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.RepositoriesLanguages) < 2 {
+		return nil
+	}
+	repositories := make([]string, 0, len(r.RepositoriesLanguages))
+	for repository := range r.RepositoriesLanguages {
+		repositories = append(repositories, repository)
+	}
+	sort.Strings(repositories)
+	fmt.Fprintln(w)
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Repository\tLanguage\tCommits\tAdded\tDeleted")
+	for _, repository := range repositories {
+		printLanguagesRows(table, repository, r.RepositoriesLanguages[repository])
+	}
+	return table.Flush()
+}
+
+// printLanguagesRows writes one row per language, the `name` only on the first one
+func printLanguagesRows(table io.Writer, name string, languages Languages) {
+	for _, language := range languages.Sorted() {
+		e := languages[language]
+		fmt.Fprintf(table, "%s\t%s\t%d\t+%d\t-%d\n", name, language, e.Commits, e.Additions, e.Deletions)
+		name = ""
+	}
+}
@@ -0,0 +1,96 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestLanguage(tt *testing.T) {
+	t := td.NewT(tt)
+
+	for name, language := range map[string]string{
+		"main.go":                "Go",
+		"web/src/App.TSX":        "TypeScript",
+		"web/index.js":           "JavaScript",
+		"Dockerfile":             "Dockerfile",
+		"build/Dockerfile.dev":   "Dockerfile",
+		"tools/api.dockerfile":   "Dockerfile",
+		"Makefile":               "Makefile",
+		"src/CMakeLists.txt":     "CMake",
+		"go.mod":                 "Go",
+		"docs/README.md":         "Markdown",
+		"LICENSE":                "Other",
+		"assets/logo.unknownext": "Other",
+	} {
+		t.Cmp(stats.Language(name), language, name)
+	}
+}
+
+func TestLanguages(tt *testing.T) {
+	t := td.NewT(tt)
+
+	monday := time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC)
+	backend := newTestRepository(tt)
+	backend.commit("main.go", "a\nb\nc\n", "Alice", "alice@corp.com", monday, "first")
+	backend.commit("main.go", "a\n", "Alice", "alice@corp.com", monday.AddDate(0, 0, 1), "second")
+	backend.commit("Makefile", "all:\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 2), "build")
+	frontend := newTestRepository(tt)
+	frontend.commit("app.tsx", "x\ny\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 3), "pair\n\nCo-authored-by: Carol <carol@corp.com>")
+
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache:        true,
+		Folders:        []string{backend.path, frontend.path},
+		Merge:          true,
+		Since:          "2025-02",
+		Until:          "2025-02",
+		CoAuthorCredit: stats.SplitCredit,
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	t.CmpNoError(results[0].Error)
+	r := results[0]
+
+	t.Cmp(r.Languages, stats.Languages{
+		"Go":         {Commits: 2, Additions: 3, Deletions: 2},
+		"Makefile":   {Commits: 1, Additions: 1},
+		"TypeScript": {Commits: 1, Additions: 2},
+	})
+	t.Cmp(r.Languages.Sorted(), []string{"Go", "TypeScript", "Makefile"})
+	t.Cmp(stats.Summarize(results).Languages, r.Languages)
+	t.Cmp(r.AuthorsLanguages, map[string]stats.Languages{
+		"Alice": {"Go": {Commits: 2, Additions: 3, Deletions: 2}},
+		"Bob":   {"Makefile": {Commits: 1, Additions: 1}, "TypeScript": {Commits: 1, Additions: 1}},
+		"Carol": {"TypeScript": {Commits: 1, Additions: 1}},
+	})
+	t.Cmp(r.RepositoriesLanguages, map[string]stats.Languages{
+		backend.path:  {"Go": {Commits: 2, Additions: 3, Deletions: 2}, "Makefile": {Commits: 1, Additions: 1}},
+		frontend.path: {"TypeScript": {Commits: 1, Additions: 2}},
+	})
+
+	var out bytes.Buffer
+	t.CmpNoError(stats.PrintLanguages(&out, r, 2))
+	t.Cmp(out.String(), td.HasPrefix(""+
+		"Language    Commits  Added  Deleted  Share\n"+
+		"Go          2        +3     -2       62%\n"+
+		"TypeScript  1        +2     -0       25%\n"+
+		"Makefile    1        +1     -0       12%\n"+
+		"\n"+
+		"Author  Language    Commits  Added  Deleted\n"+
+		"Alice   Go          2        +3     -2\n"+
+		"Bob     Makefile    1        +1     -0\n"+
+		"        TypeScript  1        +1     -0\n"+
+		"\n"+
+		"Repository"))
+
+	out.Reset()
+	t.CmpNoError(stats.WriteHTML(&out, stats.Summarize(results)))
+	t.Cmp(out.String(), td.All(
+		td.Contains("<h2>Languages</h2>"),
+		td.Re(`(?s)<td>Go</td><td class="number">2</td><td class="number additions">\+3</td><td class="number deletions">-2</td><td class="number">62%</td>`+
+			`.*<td>TypeScript</td>.*<td>Makefile</td>`),
+	))
+}
@@ -0,0 +1,183 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// LeaderboardSort is the value ranking the authors of the leaderboard
+type LeaderboardSort int
+
+const (
+	// SortByCommits ranks the authors by number of commits
+	SortByCommits LeaderboardSort = 0
+	// SortByActiveDays ranks the authors by number of days with commits
+	SortByActiveDays LeaderboardSort = 1
+	// SortByAdditions ranks the authors by number of lines added
+	SortByAdditions LeaderboardSort = 2
+	// SortByDeletions ranks the authors by number of lines deleted
+	SortByDeletions LeaderboardSort = 3
+	// SortByNet ranks the authors by lines added minus lines deleted
+	SortByNet LeaderboardSort = 4
+	// SortByFiles ranks the authors by number of files edited
+	SortByFiles LeaderboardSort = 5
+)
+
+var leaderboardSorts = []string{"commits", "active-days", "additions", "deletions", "net", "files"}
+
+// ParseLeaderboardSort returns the ranking matching the `--sort` parameter value
+func ParseLeaderboardSort(value string) (LeaderboardSort, error) {
+	if value == "" {
+		return SortByCommits, nil
+	}
+	for i, name := range leaderboardSorts {
+		if value == name {
+			return LeaderboardSort(i), nil
+		}
+	}
+	return SortByCommits, fmt.Errorf("invalid sort %s, use one of: commits, active-days, additions, deletions, net, files", value)
+}
+
+func (s LeaderboardSort) String() string {
+	return leaderboardSorts[s]
+}
+
+// value returns the value of the author activity ranked by the sort
+func (s LeaderboardSort) value(a AuthorActivity) int {
+	switch s {
+	case SortByActiveDays:
+		return a.ActiveDays
+	case SortByAdditions:
+		return a.Additions
+	case SortByDeletions:
+		return a.Deletions
+	case SortByNet:
+		return a.Net()
+	case SortByFiles:
+		return a.Files
+	default:
+		return a.Commits
+	}
+}
+
+// Ranking returns the authors of the result ranked by decreasing `sortBy` value,
+// then by decreasing number of commits, only the `top` first ones if positive.
+// With a users filter, only the authors it designates are ranked, not their co-authors.
+func (r *StatsResult) Ranking(sortBy LeaderboardSort, top int) []AuthorActivity {
+	authors := r.Authors(0)
+	if r.Options.EmailOrUsername != nil {
+		requested := authors[:0]
+		for _, a := range authors {
+			if r.RequestedAuthors[a.Author] {
+				requested = append(requested, a)
+			}
+		}
+		authors = requested
+	}
+	// authors are already sorted by commits and name
+	sort.SliceStable(authors, func(i, j int) bool {
+		return sortBy.value(authors[i]) > sortBy.value(authors[j])
+	})
+	if top > 0 && len(authors) > top {
+		authors = authors[:top]
+	}
+	return authors
+}
+
+// Leaderboard ranks the authors of all the folders by `sortBy` and prints
+// the `TopAuthors` first ones as a table or as JSON
+func Leaderboard(opts LaunchOptions, sortBy LeaderboardSort) error {
+	opts.Merge = true
+	results, err := analyzeWithProgress(opts)
+	if err != nil {
+		return err
+	}
+	r := results[0]
+	if !r.analyzed() {
+		return r.Error
+	}
+	ranking := r.Ranking(sortBy, opts.TopAuthors)
+	if opts.Output == JSON {
+		err = PrintLeaderboardJSON(os.Stdout, r, sortBy, ranking)
+	} else {
+		fmt.Println()
+		printHeader(r)
+		err = PrintLeaderboard(os.Stdout, ranking)
+	}
+	if err != nil {
+		return err
+	}
+	return reportFailures(opts, results)
+}
+
+// PrintLeaderboard writes the ranking as a table
+func PrintLeaderboard(w io.Writer, ranking []AuthorActivity) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "#\tAuthor\tCommits\tActive days\tAdded\tDeleted\tNet\tFiles")
+	for i, a := range ranking {
+		fmt.Fprintf(table, "%d\t%s\t%d\t%d\t+%d\t-%d\t%+d\t%d\n", i+1, a.Author, a.Commits, a.ActiveDays, a.Additions, a.Deletions, a.Net(), a.Files)
+	}
+	return table.Flush()
+}
+
+// JSONLeaderboard is the document printed by the leaderboard with the `--output json` option.
+type JSONLeaderboard struct {
+	// SchemaVersion is the JSONSchemaVersion used to build the document
+	SchemaVersion int `json:"schemaVersion"`
+	// GeneratedAt is the date of the report (RFC 3339)
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Folders is the list of the scanned repositories
+	Folders []string `json:"folders"`
+	// BeginOfScan and EndOfScan are the scan window bounds (RFC 3339)
+	BeginOfScan time.Time `json:"beginOfScan"`
+	EndOfScan   time.Time `json:"endOfScan"`
+	// SortedBy is the value ranking the authors
+	SortedBy string `json:"sortedBy"`
+	// Authors holds the ranked authors, best first
+	Authors []JSONRankedAuthor `json:"authors"`
+}
+
+// JSONRankedAuthor is the activity of an author in the leaderboard.
+type JSONRankedAuthor struct {
+	Rank       int    `json:"rank"`
+	Name       string `json:"name"`
+	Commits    int    `json:"commits"`
+	ActiveDays int    `json:"activeDays"`
+	Additions  int    `json:"additions"`
+	Deletions  int    `json:"deletions"`
+	NetLines   int    `json:"netLines"`
+	Files      int    `json:"files"`
+}
+
+// PrintLeaderboardJSON writes the ranking of the authors of the result as JSON
+func PrintLeaderboardJSON(w io.Writer, r *StatsResult, sortBy LeaderboardSort, ranking []AuthorActivity) error {
+	leaderboard := JSONLeaderboard{
+		SchemaVersion: JSONSchemaVersion,
+		GeneratedAt:   time.Now(),
+		Folders:       r.Options.Folders,
+		BeginOfScan:   r.BeginOfScan,
+		EndOfScan:     r.EndOfScan,
+		SortedBy:      sortBy.String(),
+		Authors:       make([]JSONRankedAuthor, 0, len(ranking)),
+	}
+	for i, a := range ranking {
+		leaderboard.Authors = append(leaderboard.Authors, JSONRankedAuthor{
+			Rank:       i + 1,
+			Name:       a.Author,
+			Commits:    a.Commits,
+			ActiveDays: a.ActiveDays,
+			Additions:  a.Additions,
+			Deletions:  a.Deletions,
+			NetLines:   a.Net(),
+			Files:      a.Files,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(leaderboard)
+}
@@ -0,0 +1,100 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestLeaderboard(tt *testing.T) {
+	t := td.NewT(tt)
+
+	monday := time.Date(2025, time.February, 3, 10, 0, 0, 0, time.UTC)
+	repo := newTestRepository(tt)
+	repo.commit("a.txt", "a\nb\nc\n", "Alice", "alice@corp.com", monday, "first")
+	repo.commit("a.txt", "a\n", "Alice", "alice@corp.com", monday.AddDate(0, 0, 1), "second")
+	repo.commit("b.txt", "b\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 2), "third")
+	repo.commit("c.txt", "c\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 2), "fourth")
+	repo.commit("d.txt", "d\n", "Bob", "bob@corp.com", monday.AddDate(0, 0, 2), "fifth")
+
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		Folders: []string{repo.path},
+		Merge:   true,
+		Since:   "2025-02",
+		Until:   "2025-02",
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	t.CmpNoError(results[0].Error)
+	r := results[0]
+
+	names := func(ranking []stats.AuthorActivity) []string {
+		authors := []string{}
+		for _, a := range ranking {
+			authors = append(authors, a.Author)
+		}
+		return authors
+	}
+	t.Cmp(names(r.Ranking(stats.SortByCommits, 0)), []string{"Bob", "Alice"})
+	t.Cmp(names(r.Ranking(stats.SortByActiveDays, 0)), []string{"Alice", "Bob"})
+	t.Cmp(names(r.Ranking(stats.SortByAdditions, 0)), []string{"Bob", "Alice"})
+	t.Cmp(names(r.Ranking(stats.SortByDeletions, 0)), []string{"Alice", "Bob"})
+	t.Cmp(names(r.Ranking(stats.SortByNet, 0)), []string{"Bob", "Alice"})
+	t.Cmp(names(r.Ranking(stats.SortByFiles, 1)), []string{"Bob"})
+
+	// several users are ranked together
+	repo.commit("e.txt", "e\n", "Carol", "carol@corp.com", monday.AddDate(0, 0, 3), "sixth")
+	repo.commit("f.txt", "f\n", "Alice", "alice@corp.com", monday.AddDate(0, 0, 4), "pair\n\nCo-authored-by: Dan <dan@corp.com>")
+	config, err := stats.LoadConfig(filepath.Join(tt.TempDir(), "missing.yaml"), true)
+	t.CmpNoError(err)
+	users, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		User:    config.ExpandUsers([]string{"Alice", "carol@corp.com"}),
+		Folders: []string{repo.path},
+		Merge:   true,
+		Since:   "2025-02",
+		Until:   "2025-02",
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	// the co-authors of the commits of the users are not ranked
+	t.Cmp(users[0].AuthorsCommits, td.ContainsKey("Dan"))
+	t.Cmp(names(users[0].Ranking(stats.SortByCommits, 0)), []string{"Alice", "Carol"})
+
+	var table bytes.Buffer
+	t.CmpNoError(stats.PrintLeaderboard(&table, r.Ranking(stats.SortByDeletions, 0)))
+	t.Cmp(table.String(), ""+
+		"#  Author  Commits  Active days  Added  Deleted  Net  Files\n"+
+		"1  Alice   2        2            +3     -2       +1   1\n"+
+		"2  Bob     3        1            +3     -0       +3   3\n")
+
+	var document bytes.Buffer
+	t.CmpNoError(stats.PrintLeaderboardJSON(&document, r, stats.SortByNet, r.Ranking(stats.SortByNet, 1)))
+	var leaderboard stats.JSONLeaderboard
+	t.CmpNoError(json.Unmarshal(document.Bytes(), &leaderboard))
+	t.Cmp(leaderboard.SortedBy, "net")
+	t.Cmp(leaderboard.Authors, []stats.JSONRankedAuthor{
+		{Rank: 1, Name: "Bob", Commits: 3, ActiveDays: 1, Additions: 3, Deletions: 0, NetLines: 3, Files: 3},
+	})
+}
+
+func TestParseLeaderboardSort(tt *testing.T) {
+	t := td.NewT(tt)
+
+	sortBy, err := stats.ParseLeaderboardSort("active-days")
+	t.CmpNoError(err)
+	t.Cmp(sortBy, stats.SortByActiveDays)
+	t.Cmp(sortBy.String(), "active-days")
+
+	sortBy, err = stats.ParseLeaderboardSort("")
+	t.CmpNoError(err)
+	t.Cmp(sortBy, stats.SortByCommits)
+
+	_, err = stats.ParseLeaderboardSort("lines")
+	t.CmpError(err)
+}
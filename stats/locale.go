@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WeekStart is the first day of the weeks, the first row of the heatmaps
+type WeekStart int
+
+const (
+	// MondayStart starts the weeks on monday
+	MondayStart WeekStart = 0
+	// SundayStart starts the weeks on sunday
+	SundayStart WeekStart = 1
+	// SaturdayStart starts the weeks on saturday
+	SaturdayStart WeekStart = 2
+)
+
+// ParseWeekStart returns the first day of week matching the `--week-start` parameter value
+func ParseWeekStart(value string) (WeekStart, error) {
+	switch strings.ToLower(value) {
+	case "", "monday":
+		return MondayStart, nil
+	case "sunday":
+		return SundayStart, nil
+	case "saturday":
+		return SaturdayStart, nil
+	default:
+		return MondayStart, fmt.Errorf("invalid week start %s, use one of: sunday, monday, saturday", value)
+	}
+}
+
+// Weekday returns the first day of the weeks
+func (s WeekStart) Weekday() time.Weekday {
+	switch s {
+	case SundayStart:
+		return time.Sunday
+	case SaturdayStart:
+		return time.Saturday
+	default:
+		return time.Monday
+	}
+}
+
+// Weekdays returns the days of the week, beginning with the first one
+func (s WeekStart) Weekdays() []time.Weekday {
+	weekdays := make([]time.Weekday, 7)
+	for i := range weekdays {
+		weekdays[i] = (s.Weekday() + time.Weekday(i)) % 7
+	}
+	return weekdays
+}
+
+// startOfWeek returns the beginning of the first day of the week containing `t`
+func (s WeekStart) startOfWeek(t time.Time) time.Time {
+	gap := (int(t.Weekday()) - int(s.Weekday()) + 7) % 7
+	return getBeginningOfDay(t).AddDate(0, 0, -gap)
+}
+
+// Locale holds the short names of the months and weekdays of a language
+type Locale struct {
+	// Months are the 3 letters names of the months, january first
+	Months [12]string
+	// Weekdays are the distinct 2 or 3 letters names of the weekdays, sunday first
+	Weekdays [7]string
+}
+
+// DefaultLocale is the locale used when none is given
+const DefaultLocale = "en"
+
+var locales = map[string]Locale{
+	"en": {
+		Months:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Weekdays: [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+	},
+	"fr": {
+		Months:   [12]string{"Jan", "Fév", "Mar", "Avr", "Mai", "Jun", "Jul", "Aoû", "Sep", "Oct", "Nov", "Déc"},
+		Weekdays: [7]string{"Di", "Lu", "Ma", "Me", "Je", "Ve", "Sa"},
+	},
+	"de": {
+		Months:   [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Weekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	},
+	"es": {
+		Months:   [12]string{"Ene", "Feb", "Mar", "Abr", "May", "Jun", "Jul", "Ago", "Sep", "Oct", "Nov", "Dic"},
+		Weekdays: [7]string{"Do", "Lu", "Ma", "Mi", "Ju", "Vi", "Sá"},
+	},
+	"it": {
+		Months:   [12]string{"Gen", "Feb", "Mar", "Apr", "Mag", "Giu", "Lug", "Ago", "Set", "Ott", "Nov", "Dic"},
+		Weekdays: [7]string{"Do", "Lu", "Ma", "Me", "Gi", "Ve", "Sa"},
+	},
+	"pt": {
+		Months:   [12]string{"Jan", "Fev", "Mar", "Abr", "Mai", "Jun", "Jul", "Ago", "Set", "Out", "Nov", "Dez"},
+		Weekdays: [7]string{"Dom", "Seg", "Ter", "Qua", "Qui", "Sex", "Sáb"},
+	},
+	"nl": {
+		Months:   [12]string{"Jan", "Feb", "Mrt", "Apr", "Mei", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dec"},
+		Weekdays: [7]string{"Zo", "Ma", "Di", "Wo", "Do", "Vr", "Za"},
+	},
+}
+
+// ParseLocale returns the locale matching the `--locale` parameter value,
+// a language code optionally followed by a territory and an encoding (fr, fr_FR.UTF-8)
+func ParseLocale(value string) (Locale, error) {
+	language := strings.ToLower(value)
+	if i := strings.IndexAny(language, "_-."); i >= 0 {
+		language = language[:i]
+	}
+	if language == "" || language == "c" || language == "posix" {
+		language = DefaultLocale
+	}
+	locale, ok := locales[language]
+	if !ok {
+		return locales[DefaultLocale], fmt.Errorf("unsupported locale %s, use one of: %s", value, strings.Join(Locales(), ", "))
+	}
+	return locale, nil
+}
+
+// Locales returns the supported languages codes
+func Locales() []string {
+	var names []string
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// locale returns the locale of the options, the default one if not valid
+func (o StatsOptions) locale() Locale {
+	locale, _ := ParseLocale(o.Locale)
+	return locale
+}
+
+// month returns the short name of the month
+func (l Locale) month(m time.Month) string {
+	return l.Months[m-1]
+}
+
+// weekday returns the short name of the weekday
+func (l Locale) weekday(d time.Weekday) string {
+	return l.Weekdays[d]
+}
+
+// date returns the day formatted with its weekday, day of month, month and year
+func (l Locale) date(t time.Time) string {
+	return fmt.Sprintf("%s %d %s %d", l.weekday(t.Weekday()), t.Day(), l.month(t.Month()), t.Year())
+}
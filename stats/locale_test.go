@@ -0,0 +1,80 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestParseWeekStart(tt *testing.T) {
+	t := td.NewT(tt)
+
+	for value, expected := range map[string]time.Weekday{"": time.Monday, "monday": time.Monday, "Sunday": time.Sunday, "saturday": time.Saturday} {
+		start, err := stats.ParseWeekStart(value)
+		t.CmpNoError(err)
+		t.Cmp(start.Weekday(), expected, value)
+	}
+	_, err := stats.ParseWeekStart("friday")
+	t.CmpError(err)
+
+	t.Cmp(stats.SaturdayStart.Weekdays(), []time.Weekday{
+		time.Saturday, time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+	})
+}
+
+func TestParseLocale(tt *testing.T) {
+	t := td.NewT(tt)
+
+	locale, err := stats.ParseLocale("")
+	t.CmpNoError(err)
+	t.Cmp(locale.Weekdays[time.Monday], "Mo")
+
+	locale, err = stats.ParseLocale("fr_FR.UTF-8")
+	t.CmpNoError(err)
+	t.Cmp(locale.Months[time.August-1], "Aoû")
+	t.Cmp(locale.Weekdays[time.Monday], "Lu")
+
+	_, err = stats.ParseLocale("xx")
+	t.Cmp(err, td.Contains("unsupported locale xx"))
+
+	// the rows of the heatmaps must be told apart
+	for _, language := range stats.Locales() {
+		locale, err := stats.ParseLocale(language)
+		t.CmpNoError(err)
+		seen := map[string]bool{}
+		for _, weekday := range locale.Weekdays {
+			t.False(seen[weekday], "%s weekday %s is not unique", language, weekday)
+			t.Cmp(len([]rune(weekday)), td.Between(2, 3), language)
+			seen[weekday] = true
+		}
+	}
+}
+
+func TestWeekStartAlignment(tt *testing.T) {
+	t := td.NewT(tt)
+
+	for _, start := range []stats.WeekStart{stats.MondayStart, stats.SundayStart, stats.SaturdayStart} {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache:         true,
+			DurationInWeeks: 4,
+			Folders:         currentRepo,
+			Merge:           true,
+			WeekStart:       start,
+			Locale:          "de",
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+		t.Cmp(results[0].BeginOfScan.Weekday(), start.Weekday())
+
+		var out bytes.Buffer
+		t.CmpNoError(stats.WriteSVG(&out, results[0]))
+		t.Cmp(out.String(), td.Contains(">Di<"), "german weekdays")
+	}
+
+	_, err := stats.NewAnalyzer(stats.LaunchOptions{Folders: currentRepo, Locale: "xx"}).Run(context.Background())
+	t.CmpError(err)
+}
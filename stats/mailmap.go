@@ -0,0 +1,183 @@
+package stats
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+const mailmapFileName = ".mailmap"
+
+// Mailmap maps the identities found in commits to canonical ones,
+// following the format described in gitmailmap(5).
+type Mailmap struct {
+	entries []mailmapEntry
+}
+
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+	commitEmail string
+}
+
+// ParseMailmap given the content of a mailmap file returns the parsed mailmap.
+// Malformed lines are ignored like git does.
+func ParseMailmap(content string) *Mailmap {
+	m := &Mailmap{}
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		entry, ok := parseMailmapLine(line)
+		if ok {
+			m.entries = append(m.entries, entry)
+		}
+	}
+	return m
+}
+
+// parseMailmapLine parses one of the supported forms:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func parseMailmapLine(line string) (mailmapEntry, bool) {
+	var names []string
+	var emails []string
+	rest := line
+	for {
+		open := strings.Index(rest, "<")
+		if open < 0 {
+			break
+		}
+		closing := strings.Index(rest[open:], ">")
+		if closing < 0 {
+			return mailmapEntry{}, false
+		}
+		names = append(names, strings.TrimSpace(rest[:open]))
+		emails = append(emails, strings.TrimSpace(rest[open+1:open+closing]))
+		rest = rest[open+closing+1:]
+	}
+
+	switch len(emails) {
+	case 1:
+		if names[0] == "" {
+			return mailmapEntry{}, false
+		}
+		return mailmapEntry{properName: names[0], commitEmail: emails[0]}, true
+	case 2:
+		return mailmapEntry{
+			properName:  names[0],
+			properEmail: emails[0],
+			commitName:  names[1],
+			commitEmail: emails[1],
+		}, true
+	default:
+		return mailmapEntry{}, false
+	}
+}
+
+// Merge appends the entries of `other`, which then take precedence
+// over the existing ones.
+func (m *Mailmap) Merge(other *Mailmap) {
+	if other == nil {
+		return
+	}
+	m.entries = append(m.entries, other.entries...)
+}
+
+// Resolve returns the canonical name and email of the given commit identity.
+// Entries matching both name and email win over entries matching only the email,
+// and the last matching entry wins among entries of the same kind.
+func (m *Mailmap) Resolve(name string, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+	var byEmail *mailmapEntry
+	var byNameAndEmail *mailmapEntry
+	for i := range m.entries {
+		e := &m.entries[i]
+		if !strings.EqualFold(e.commitEmail, email) {
+			continue
+		}
+		if e.commitName == "" {
+			byEmail = e
+		} else if strings.EqualFold(e.commitName, name) {
+			byNameAndEmail = e
+		}
+	}
+	match := byNameAndEmail
+	if match == nil {
+		match = byEmail
+	}
+	if match == nil {
+		return name, email
+	}
+	if match.properName != "" {
+		name = match.properName
+	}
+	if match.properEmail != "" {
+		email = match.properEmail
+	}
+	return name, email
+}
+
+// ReadMailmapFile reads and parses the mailmap file located at `filePath`.
+func ReadMailmapFile(filePath string) (*Mailmap, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMailmap(string(content)), nil
+}
+
+// loadMailmap returns the mailmap of the repository found in `path`, read from
+// the working tree or from HEAD for bare repositories, merged with the optional
+// user level mailmap file.
+func loadMailmap(repo *git.Repository, path string, userMailmapFile string) (*Mailmap, error) {
+	m := &Mailmap{}
+	repoMailmap, err := ReadMailmapFile(filepath.Join(path, mailmapFileName))
+	switch {
+	case err == nil:
+		m.Merge(repoMailmap)
+	case errors.Is(err, os.ErrNotExist):
+		m.Merge(readMailmapFromHead(repo))
+	default:
+		return nil, err
+	}
+
+	if userMailmapFile != "" {
+		userMailmap, err := ReadMailmapFile(userMailmapFile)
+		if err != nil {
+			return nil, err
+		}
+		m.Merge(userMailmap)
+	}
+	return m, nil
+}
+
+// readMailmapFromHead returns the mailmap committed in HEAD if any.
+func readMailmapFromHead(repo *git.Repository) *Mailmap {
+	head, err := repo.Head()
+	if err != nil {
+		return nil
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil
+	}
+	file, err := commit.File(mailmapFileName)
+	if err != nil {
+		return nil
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil
+	}
+	return ParseMailmap(content)
+}
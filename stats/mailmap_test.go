@@ -0,0 +1,56 @@
+package stats_test
+
+import (
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+var mailmapContent = `
+# comments are ignored
+Steeve Vandecappelle <steeve@home.com>
+<steeve@corp.com> <steeve@laptop.local>
+Steeve Vandecappelle <steeve@corp.com> svandecappelle <steeve@old.com>
+Other Name <other@corp.com> Other <other@old.com> # trailing comment
+malformed line
+`
+
+func TestMailmapResolve(tt *testing.T) {
+	t := td.NewT(tt)
+	m := stats.ParseMailmap(mailmapContent)
+
+	name, email := m.Resolve("steeve", "steeve@home.com")
+	t.Cmp(name, "Steeve Vandecappelle")
+	t.Cmp(email, "steeve@home.com")
+
+	name, email = m.Resolve("Steeve", "Steeve@Laptop.local")
+	t.Cmp(name, "Steeve")
+	t.Cmp(email, "steeve@corp.com")
+
+	name, email = m.Resolve("svandecappelle", "steeve@old.com")
+	t.Cmp(name, "Steeve Vandecappelle")
+	t.Cmp(email, "steeve@corp.com")
+
+	name, email = m.Resolve("Someone else", "steeve@old.com")
+	t.Cmp(name, "Someone else", "name and email entries need both to match")
+	t.Cmp(email, "steeve@old.com")
+
+	name, email = m.Resolve("Other", "other@old.com")
+	t.Cmp(name, "Other Name")
+	t.Cmp(email, "other@corp.com")
+}
+
+func TestMailmapMergePrecedence(tt *testing.T) {
+	t := td.NewT(tt)
+	m := stats.ParseMailmap("Repo Name <me@corp.com>")
+	m.Merge(stats.ParseMailmap("User Name <me@corp.com>"))
+
+	name, _ := m.Resolve("me", "me@corp.com")
+	t.Cmp(name, "User Name")
+
+	var empty *stats.Mailmap
+	name, email := empty.Resolve("me", "me@corp.com")
+	t.Cmp(name, "me")
+	t.Cmp(email, "me@corp.com")
+}
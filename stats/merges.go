@@ -0,0 +1,85 @@
+package stats
+
+import "fmt"
+
+// MergesPolicy selects the commits counted according to their number of parents
+type MergesPolicy int
+
+const (
+	// IncludeMerges counts the merge commits as the other commits
+	IncludeMerges MergesPolicy = 0
+	// ExcludeMerges ignores the merge commits
+	ExcludeMerges MergesPolicy = 1
+	// OnlyMerges only counts the merge commits
+	OnlyMerges MergesPolicy = 2
+)
+
+// ParseMergesPolicy returns the policy matching the `--merges` parameter value
+func ParseMergesPolicy(value string) (MergesPolicy, error) {
+	switch value {
+	case "", "include":
+		return IncludeMerges, nil
+	case "exclude":
+		return ExcludeMerges, nil
+	case "only":
+		return OnlyMerges, nil
+	default:
+		return IncludeMerges, fmt.Errorf("invalid merges policy %s, use one of: include, exclude, only", value)
+	}
+}
+
+// selects returns true if the commit is counted by the policy
+func (p MergesPolicy) selects(c *CommitRecord) bool {
+	switch p {
+	case ExcludeMerges:
+		return !c.isMerge()
+	case OnlyMerges:
+		return c.isMerge()
+	default:
+		return true
+	}
+}
+
+// MergeLines is the way the lines edited by merge commits are counted
+type MergeLines int
+
+const (
+	// AutoMergeLines counts the merge lines against the first parent when following
+	// first parents only, and skips them otherwise as they were counted on the merged commits
+	AutoMergeLines MergeLines = 0
+	// FirstParentMergeLines counts the lines of the diff between the merge and its first parent
+	FirstParentMergeLines MergeLines = 1
+	// SkipMergeLines never counts the lines of merge commits
+	SkipMergeLines MergeLines = 2
+)
+
+// ParseMergeLines returns the merge lines counting matching the `--merge-lines` parameter value
+func ParseMergeLines(value string) (MergeLines, error) {
+	switch value {
+	case "", "auto":
+		return AutoMergeLines, nil
+	case "first-parent":
+		return FirstParentMergeLines, nil
+	case "skip":
+		return SkipMergeLines, nil
+	default:
+		return AutoMergeLines, fmt.Errorf("invalid merge lines %s, use one of: auto, first-parent, skip", value)
+	}
+}
+
+// countsMergeLines returns true if the lines of the merge commits are counted
+func (o StatsOptions) countsMergeLines() bool {
+	switch o.MergeLines {
+	case FirstParentMergeLines:
+		return true
+	case SkipMergeLines:
+		return false
+	default:
+		return o.FirstParent
+	}
+}
+
+// isMerge returns true if the commit has several parents
+func (c *CommitRecord) isMerge() bool {
+	return len(c.Parents) > 1
+}
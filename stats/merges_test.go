@@ -0,0 +1,65 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestMergesPolicy(tt *testing.T) {
+	t := td.NewT(tt)
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	repo := newTestRepository(tt)
+	base := repo.commit("main.go", "package main\n", "Alice", "alice@corp.com", yesterday, "init")
+	repo.checkout("feature", true)
+	repo.commit("feature.go", "package main\n\nfunc a() {}\n", "Bob", "bob@corp.com", yesterday, "feature a")
+	feature := repo.commit("feature.go", "package main\n\nfunc a() {}\n\nfunc b() {}\n", "Bob", "bob@corp.com", yesterday, "feature b")
+	repo.checkout("master", false)
+	signature := &object.Signature{Name: "Alice", Email: "alice@corp.com", When: yesterday}
+	repo.commitAs("feature.go", "package main\n\nfunc a() {}\n\nfunc b() {}\n", signature, signature, "merge feature", base, feature)
+
+	run := func(opts stats.LaunchOptions) (int, int) {
+		opts.DurationInWeeks = 2
+		opts.Folders = []string{repo.path}
+		opts.Merge = true
+		opts.NoCache = true
+		results, err := stats.NewAnalyzer(opts).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+		commits, additions := 0, 0
+		for _, c := range results[0].Commits {
+			commits += c
+		}
+		for _, editions := range results[0].AuthorsEditions {
+			additions += editions["additions"]
+		}
+		return commits, additions
+	}
+
+	commits, additions := run(stats.LaunchOptions{})
+	t.Cmp(commits, 4)
+	t.Cmp(additions, 6, "merge lines are skipped, they were counted on the feature commits")
+
+	commits, _ = run(stats.LaunchOptions{Merges: stats.ExcludeMerges})
+	t.Cmp(commits, 3)
+
+	commits, additions = run(stats.LaunchOptions{Merges: stats.OnlyMerges})
+	t.Cmp(commits, 1)
+	t.Cmp(additions, 0)
+
+	commits, additions = run(stats.LaunchOptions{FirstParent: true})
+	t.Cmp(commits, 2)
+	t.Cmp(additions, 6, "merge lines are compared with the first parent")
+
+	commits, additions = run(stats.LaunchOptions{FirstParent: true, MergeLines: stats.SkipMergeLines})
+	t.Cmp(commits, 2)
+	t.Cmp(additions, 1)
+
+	_, additions = run(stats.LaunchOptions{MergeLines: stats.FirstParentMergeLines})
+	t.Cmp(additions, 11, "merge lines counted twice")
+}
@@ -0,0 +1,232 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// globPrefix marks the gitignore style globs patterns: `glob:*.lock`
+	globPrefix = "glob:"
+	// regexpPrefix optionally marks the regular expressions patterns: `re:\.lock$`
+	regexpPrefix = "re:"
+	// pathspecPrefix marks the git pathspecs patterns: `:(exclude)vendor/**`
+	pathspecPrefix = ":"
+)
+
+// filePatterns holds the compiled patterns of the files to exclude or include
+type filePatterns struct {
+	exclude []*regexp.Regexp
+	include []*regexp.Regexp
+}
+
+// compilePatterns compiles once the files patterns of all the repositories. A pattern is
+// a regular expression, a gitignore style glob prefixed by `glob:` or a git pathspec
+// like `:(exclude)vendor/**`. Excluding pathspecs exclude their files even when given
+// as include patterns.
+func compilePatterns(exclude []string, include []string) (*filePatterns, error) {
+	patterns := &filePatterns{}
+	for _, pattern := range exclude {
+		pR, _, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file exclude pattern %s: %w", pattern, err)
+		}
+		patterns.exclude = append(patterns.exclude, pR)
+	}
+	for _, pattern := range include {
+		pR, excluding, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file include pattern %s: %w", pattern, err)
+		}
+		if excluding {
+			patterns.exclude = append(patterns.exclude, pR)
+		} else {
+			patterns.include = append(patterns.include, pR)
+		}
+	}
+	return patterns, nil
+}
+
+// CheckFilePatterns returns an error if one of the files patterns is invalid, to report
+// it before the repositories are analyzed
+func CheckFilePatterns(exclude []string, include []string) error {
+	_, err := compilePatterns(exclude, include)
+	return err
+}
+
+// empty returns true if no file is filtered out
+func (p *filePatterns) empty() bool {
+	return len(p.exclude) == 0 && len(p.include) == 0
+}
+
+// ignore returns true if the file `name` is excluded or not included by the patterns
+func (p *filePatterns) ignore(name string) bool {
+	for _, pR := range p.exclude {
+		if pR.MatchString(name) {
+			return true
+		}
+	}
+	if len(p.include) == 0 {
+		return false
+	}
+	for _, pR := range p.include {
+		if pR.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// compilePattern compiles the pattern to a regular expression matching the files paths,
+// and returns true if it is an excluding pathspec
+func compilePattern(pattern string) (*regexp.Regexp, bool, error) {
+	switch {
+	case strings.HasPrefix(pattern, pathspecPrefix):
+		return compilePathspec(strings.TrimPrefix(pattern, pathspecPrefix))
+	case strings.HasPrefix(pattern, globPrefix):
+		glob := strings.TrimPrefix(pattern, globPrefix)
+		if strings.Trim(glob, "/") == "" {
+			return nil, false, errors.New("empty glob")
+		}
+		pR, err := regexp.Compile(globRegexp(glob))
+		return pR, false, err
+	default:
+		pR, err := regexp.Compile(strings.TrimPrefix(pattern, regexpPrefix))
+		return pR, false, err
+	}
+}
+
+// compilePathspec compiles a git pathspec, relative to the repository root, with its
+// magic words `exclude` (or `!` and `^`), `glob`, `icase`, `literal` and `top` (or `/`).
+// As for git, wildcards match `/` unless the `glob` magic is given, and a pathspec
+// matching a directory matches all its files.
+func compilePathspec(pathspec string) (*regexp.Regexp, bool, error) {
+	var magic []string
+	if strings.HasPrefix(pathspec, "(") {
+		end := strings.Index(pathspec, ")")
+		if end < 0 {
+			return nil, false, errors.New("missing closing ) of pathspec magic")
+		}
+		magic = strings.Split(pathspec[1:end], ",")
+		pathspec = pathspec[end+1:]
+	} else {
+		for len(pathspec) > 0 && strings.ContainsRune("!^/", rune(pathspec[0])) {
+			if pathspec[0] == '/' {
+				magic = append(magic, "top")
+			} else {
+				magic = append(magic, "exclude")
+			}
+			pathspec = pathspec[1:]
+		}
+		// an optional colon ends the short magic
+		pathspec = strings.TrimPrefix(pathspec, ":")
+	}
+
+	exclude, glob, literal, icase := false, false, false, false
+	for _, word := range magic {
+		switch strings.TrimSpace(word) {
+		case "exclude":
+			exclude = true
+		case "glob":
+			glob = true
+		case "literal":
+			literal = true
+		case "icase":
+			icase = true
+		case "top", "":
+		default:
+			return nil, false, fmt.Errorf("unsupported pathspec magic %s", word)
+		}
+	}
+
+	pathspec = strings.Trim(pathspec, "/")
+	expr := ".*"
+	switch {
+	case pathspec == "" || pathspec == ".":
+		// the whole repository
+	case literal:
+		expr = regexp.QuoteMeta(pathspec) + "(/.*)?"
+	default:
+		expr = wildcardRegexp(pathspec, !glob) + "(/.*)?"
+	}
+	if icase {
+		expr = "(?i)" + expr
+	}
+	pR, err := regexp.Compile("^" + expr + "$")
+	return pR, exclude, err
+}
+
+// globRegexp converts a gitignore style glob to a regular expression: a glob without
+// slash matches the files or directories of any folder, other globs are relative to
+// the repository root, and a glob matching a directory matches all its files
+func globRegexp(glob string) string {
+	files := "(/.*)?$"
+	if strings.HasSuffix(glob, "/") {
+		// only directories
+		glob = strings.TrimSuffix(glob, "/")
+		files = "/.*$"
+	}
+	prefix := "^"
+	if strings.Contains(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+	} else {
+		prefix = "^(.*/)?"
+	}
+	return prefix + wildcardRegexp(glob, false) + files
+}
+
+// wildcardRegexp converts the wildcards of a glob to a regular expression: `*` matches
+// anything but `/` unless `starMatchesSlash`, `**/` matches any number of folders,
+// `?` matches one character and `[...]` one of the characters of the class
+func wildcardRegexp(glob string, starMatchesSlash bool) string {
+	var expr strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			switch {
+			case strings.HasPrefix(glob[i:], "**/"):
+				expr.WriteString("(.*/)?")
+				i += 2
+			case strings.HasPrefix(glob[i:], "**"):
+				expr.WriteString(".*")
+				i++
+			case starMatchesSlash:
+				expr.WriteString(".*")
+			default:
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			// a closing bracket first in the class is part of it
+			j := i + 1
+			if j < len(glob) && (glob[j] == '!' || glob[j] == '^') {
+				j++
+			}
+			if j < len(glob) && glob[j] == ']' {
+				j++
+			}
+			end := strings.IndexByte(glob[j:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : j+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i = j + end
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+			}
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return expr.String()
+}
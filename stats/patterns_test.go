@@ -0,0 +1,74 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestFilePatterns(tt *testing.T) {
+	t := td.NewT(tt)
+
+	when := time.Date(2025, time.April, 7, 10, 0, 0, 0, time.UTC)
+	repo := newTestRepository(tt)
+	repo.commit("vendor/lib/lib.go", "a\nb\n", "Alice", "alice@corp.com", when, "vendor")
+	repo.commit("src/app/main.go", "a\nb\nc\n", "Alice", "alice@corp.com", when, "code")
+	repo.commit("src/app/vendor.go", "a\n", "Alice", "alice@corp.com", when, "code")
+	repo.commit("docs/README.md", "a\n", "Alice", "alice@corp.com", when, "docs")
+	repo.commit("yarn.lock", "a\nb\nc\nd\n", "Alice", "alice@corp.com", when, "lock")
+
+	// run returns the number of commits and lines added counted with the patterns
+	run := func(exclude []string, include []string) (int, int) {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache:          true,
+			Folders:          []string{repo.path},
+			Since:            "2025-04",
+			Until:            "2025-04",
+			PatternToExclude: exclude,
+			PatternToInclude: include,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+		return results[0].CommitsOn(when), results[0].AuthorsEditions["Alice"]["additions"]
+	}
+
+	for _, test := range []struct {
+		name      string
+		exclude   []string
+		include   []string
+		commits   int
+		additions int
+	}{
+		{name: "no pattern", commits: 5, additions: 11},
+		{name: "regexp", exclude: []string{`\.lock$`}, commits: 4, additions: 7},
+		{name: "explicit regexp", include: []string{`re:^src/`}, commits: 2, additions: 4},
+		{name: "glob at any depth", exclude: []string{"glob:*.lock", "glob:vendor/"}, commits: 3, additions: 5},
+		{name: "anchored glob", include: []string{"glob:src/**/*.go"}, commits: 2, additions: 4},
+		{name: "glob class", include: []string{"glob:[!m]*.go"}, commits: 2, additions: 3},
+		{name: "exclude pathspec", exclude: []string{":(exclude)vendor/**"}, commits: 4, additions: 9},
+		{name: "exclude pathspec as include", include: []string{":!vendor", ":^yarn.lock"}, commits: 3, additions: 5},
+		{name: "pathspec directory", include: []string{":src"}, commits: 2, additions: 4},
+		{name: "pathspec wildcard crosses folders", include: []string{":*.go"}, commits: 3, additions: 6},
+		{name: "glob pathspec", include: []string{":(glob)*.go"}, commits: 0, additions: 0},
+		{name: "icase pathspec", include: []string{":(icase)DOCS"}, commits: 1, additions: 1},
+	} {
+		commits, additions := run(test.exclude, test.include)
+		t.Cmp(commits, test.commits, test.name)
+		t.Cmp(additions, test.additions, test.name)
+	}
+
+	for _, pattern := range []string{"(", ":(exclude", ":(nope)vendor", "glob:/"} {
+		_, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache:          true,
+			Folders:          []string{repo.path},
+			PatternToExclude: []string{pattern},
+		}).Run(context.Background())
+		t.Cmp(err, td.Contains("invalid file exclude pattern"), pattern)
+		t.Cmp(stats.CheckFilePatterns([]string{pattern}, nil), td.Contains("invalid file exclude pattern"), pattern)
+		t.Cmp(stats.CheckFilePatterns(nil, []string{pattern}), td.Contains("invalid file include pattern"), pattern)
+	}
+	t.CmpNoError(stats.CheckFilePatterns([]string{"glob:*.lock"}, []string{":(exclude)vendor/**"}))
+}
@@ -12,13 +12,35 @@ type OutputType int64
 const (
 	Console   OutputType = 0
 	Dashboard OutputType = 1
+	JSON      OutputType = 2
 )
 
+// ParseOutputType returns the output type matching the `--output` parameter value
+func ParseOutputType(value string) (OutputType, error) {
+	switch value {
+	case "", "console":
+		return Console, nil
+	case "json":
+		return JSON, nil
+	default:
+		return Console, fmt.Errorf("invalid output %s, use one of: console, json", value)
+	}
+}
+
 type StatsResultConsolePrinter struct {
 	OutputType OutputType
 }
 
 func PrintResult(r *StatsResult) {
+	printHeader(r)
+	StatsResultConsolePrinter{Console}.print(r, -1)
+	if r.Commits != nil {
+		printActivity(r)
+	}
+}
+
+// printHeader prints the folders, the user and the period of the result
+func printHeader(r *StatsResult) {
 	o := r.Options
 	start := getBeginningOfDay(r.BeginOfScan)
 	end := getEndOfDay(r.EndOfScan)
@@ -38,9 +60,10 @@ func PrintResult(r *StatsResult) {
 	Print(Message, start.Format("January 02, 2006 15:04:05"))
 	fmt.Printf(" to ")
 	Print(Message, end.Format("January 02, 2006 15:04:05"))
+	fmt.Printf(" in ")
+	Print(Message, o.timezoneLabel())
 	fmt.Println()
 	fmt.Println()
-	StatsResultConsolePrinter{Console}.print(r, -1)
 }
 
 func (p StatsResultConsolePrinter) print(r *StatsResult, limitWeeks int) string {
@@ -70,80 +93,86 @@ func Print(c TermStyle, s string) {
 // printMonths prints the month names in the first line, determining when the month
 // changed between switching weeks
 func getMonths(r *StatsResult, limitWeeks int) string {
-	week := getBeginningOfDay(r.EndOfScan).Add(-(time.Duration(r.DurationInDays) * time.Hour * 24))
-	month := week.Month()
 	out := "    "
-	i := r.DurationInDays
-	for week.Before(r.EndOfScan) {
-		if limitWeeks > 0 && i > limitWeeks*7 {
-			i -= 7
-			week = week.Add(7 * time.Hour * 24)
-			continue
+	for _, label := range monthLabels(r, limitWeeks) {
+		if label != "" {
+			out += fmt.Sprintf(" %s", label)
+		} else {
+			out += "    "
 		}
+	}
+	out += "\n"
+	return out
+}
 
+// monthLabels returns for each week column the short name of the month
+// if it changed since the previous week, or an empty string
+func monthLabels(r *StatsResult, limitWeeks int) []string {
+	begin := r.gridBegin()
+	locale := r.Options.locale()
+	month := begin.Month()
+	labels := []string{}
+	for column := firstWeekShown(r, limitWeeks); column < r.scanWeeks(); column++ {
+		week := begin.AddDate(0, 0, column*7)
 		if week.Month() != month {
-			out += fmt.Sprintf(" %s", week.Month().String()[:3])
+			labels = append(labels, locale.month(week.Month()))
 			month = week.Month()
 		} else {
-			out += "    "
+			labels = append(labels, "")
 		}
-		week = week.Add(7 * time.Hour * 24)
 	}
-	out += "\n"
-	return out
+	return labels
 }
 
-// printDayCol given the day number (0 is Sunday) prints the day name,
-// alternating the rows (prints just 2,4,6)
-func getDayCol(day int) string {
-	days := []string{"Su ", "Mo ", "Tu ", "We ", "Th ", "Fr ", "Sa "}
-	return days[day]
+// firstWeekShown returns the first week column displayed to show only the
+// last `limitWeeks` weeks, all the weeks are shown if not positive
+func firstWeekShown(r *StatsResult, limitWeeks int) int {
+	if limitWeeks > 0 && r.scanWeeks() > limitWeeks {
+		return r.scanWeeks() - limitWeeks
+	}
+	return 0
+}
+
+// getDayCol given the weekday returns its name in the locale
+func getDayCol(day time.Weekday, locale Locale) string {
+	return fmt.Sprintf("%-3s", locale.weekday(day))
 }
 
 // getCells build a string for the cells of the graph
 func (p StatsResultConsolePrinter) getCells(keys []int, r *StatsResult, limitWeeks int) string {
 	out := ""
 	out += getMonths(r, limitWeeks)
-	durationInWeeks := r.DurationInDays / 7
 
-	begin := r.BeginOfScan // .AddDate(0, 0, int(-offset))
-	end := getEndOfDay(r.EndOfScan)
-
-	current := begin
-	for i := 0; i < 7; i += 1 {
+	begin := r.gridBegin()
+	locale := r.Options.locale()
+	theme := r.Options.theme()
+	scale := r.Options.Scale.thresholds(r)
+	first := firstWeekShown(r, limitWeeks)
+	today := getBeginningOfDay(scanNow(r.Options.Timezone))
+	for row := 0; row < 7; row += 1 {
 		// Let loop on data with starting column and adds 7 to each cell to print
-		// Then start with weekDay gap
-		current = begin.AddDate(0, 0, i)
-		weekNum := durationInWeeks
-
-		for current.Before(end) {
-			if limitWeeks > 0 && weekNum > limitWeeks {
-				weekNum -= 1
-				current = current.AddDate(0, 0, 7)
-				continue
+		line := getDayCol(begin.AddDate(0, 0, row).Weekday(), locale)
+		for column := first; column < r.scanWeeks(); column++ {
+			current := begin.AddDate(0, 0, column*7+row)
+			if current.After(r.EndOfScan) {
+				break
 			}
-			if daysBetween(r.BeginOfScan, current) < 7 {
-				// first week print weekday
-				out += getDayCol(int(current.Weekday()))
-			}
-			day := end.Sub(current).Hours() / 24
-			out += p.getCell(r.Commits[int(day+8)], current)
-
-			if daysBetween(current, r.EndOfScan) < 7 {
-				// last week return to begin
-				out += "\n"
+			if !r.inScan(current) {
+				// before the beginning of the scan
+				line += "    "
+				continue
 			}
-
-			weekNum -= 1
-			current = current.AddDate(0, 0, 7)
+			line += p.getCell(r.CommitsOn(current), current, today, theme, scale)
 		}
+		out += line + "\n"
 	}
 	return out
 }
 
 // getCell given a cell value prints it with a different format
-// based on the value amount, and on the `today` flag.
-func (p StatsResultConsolePrinter) getCell(val int, date time.Time) string {
+// based on the value intensity in the scale, and on the `today` flag, the
+// beginning of the current day in the timezone of the scan.
+func (p StatsResultConsolePrinter) getCell(val int, date time.Time, today time.Time, theme Theme, scale Scale) string {
 	str := "  %d "
 	switch {
 	case val == 0:
@@ -159,23 +188,26 @@ func (p StatsResultConsolePrinter) getCell(val int, date time.Time) string {
 		cellContent = fmt.Sprintf(str, val)
 	}
 	switch {
-	case getBeginningOfDay(date).Equal(getBeginningOfDay(time.Now())):
+	case getBeginningOfDay(date).Equal(today):
 		// today
-		return p.colorize(Today, cellContent)
+		return p.colorize(theme.Today, cellContent)
 	case date.Day() == 1:
 		// first of month
-		return p.colorize(FirstOfMonth, cellContent)
-	case val == 0:
-		return p.colorize(Empty, "  - ")
-	case val > 0 && val < 5:
-		return p.colorize(ValueLow, cellContent)
-	case val >= 5 && val < 10:
-		return p.colorize(ValueMiddle, cellContent)
-	default:
-		return p.colorize(ValueHigh, cellContent)
+		return p.colorize(theme.FirstOfMonth, cellContent)
 	}
+	return p.colorize(theme.style(scale.intensity(val)), cellContent)
 }
 
+// intensity is the activity level of a day
+type intensity int
+
+const (
+	intensityNone intensity = iota
+	intensityLow
+	intensityMiddle
+	intensityHigh
+)
+
 // sortMapIntoSlice returns a slice of indexes of a map, ordered
 func sortMapIntoSlice(r *StatsResult) []int {
 	// order map
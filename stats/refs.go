@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const headRef = "HEAD"
+
+// RefsSelection describes the refs whose history is analyzed,
+// only HEAD is walked when empty
+type RefsSelection struct {
+	// All walks every ref: HEAD, branches, remote-tracking branches and tags
+	All bool
+	// Branches holds globs of the local branches to walk, HEAD is not walked when set
+	Branches []string
+	// RemoteBranches walks the remote-tracking branches, filtered by Branches globs if any
+	RemoteBranches bool
+}
+
+// String returns a stable description of the selection, empty for HEAD only
+func (s RefsSelection) String() string {
+	var parts []string
+	if s.All {
+		parts = append(parts, "all")
+	}
+	if len(s.Branches) > 0 {
+		branches := append([]string{}, s.Branches...)
+		sort.Strings(branches)
+		parts = append(parts, "branches="+strings.Join(branches, ","))
+	}
+	if s.RemoteBranches {
+		parts = append(parts, "remotes")
+	}
+	return strings.Join(parts, ";")
+}
+
+// validate checks the branches globs syntax
+func (s RefsSelection) validate() error {
+	for _, glob := range s.Branches {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid branch pattern %s: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+// matchBranch returns true if the branch short name matches one of the globs
+func (s RefsSelection) matchBranch(name string) bool {
+	if len(s.Branches) == 0 {
+		return true
+	}
+	for _, glob := range s.Branches {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveHeads returns the commits pointed by the selected refs, keyed by ref name
+func resolveHeads(repo *git.Repository, selection RefsSelection) (map[string]*object.Commit, error) {
+	heads := make(map[string]*object.Commit)
+	if len(selection.Branches) == 0 || selection.All {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, err
+		}
+		commit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return nil, err
+		}
+		heads[headRef] = commit
+	}
+	if !selection.All && len(selection.Branches) == 0 && !selection.RemoteBranches {
+		return heads, nil
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		name := ref.Name()
+		selected := selection.All
+		switch {
+		case name.IsBranch():
+			selected = selected || (len(selection.Branches) > 0 && selection.matchBranch(name.Short()))
+		case name.IsRemote():
+			// remote branches are matched without the remote name
+			short := name.Short()
+			branch := short[strings.Index(short, "/")+1:]
+			selected = selected || (selection.RemoteBranches && selection.matchBranch(branch))
+		}
+		if !selected {
+			return nil
+		}
+		commit := peelToCommit(repo, ref.Hash())
+		if commit != nil {
+			heads[name.String()] = commit
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(heads) == 0 {
+		return nil, fmt.Errorf("no ref matching branches %s", strings.Join(selection.Branches, ","))
+	}
+	return heads, nil
+}
+
+// peelToCommit returns the commit pointed by `hash`, directly or through annotated tags,
+// nil if it does not point to a commit
+func peelToCommit(repo *git.Repository, hash plumbing.Hash) *object.Commit {
+	commit, err := repo.CommitObject(hash)
+	if err == nil {
+		return commit
+	}
+	tag, err := repo.TagObject(hash)
+	if err != nil {
+		return nil
+	}
+	commit, err = tag.Commit()
+	if err != nil {
+		return nil
+	}
+	return commit
+}
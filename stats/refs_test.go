@@ -0,0 +1,57 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestRefsSelectionString(tt *testing.T) {
+	t := td.NewT(tt)
+	t.Cmp(stats.RefsSelection{}.String(), "")
+	t.Cmp(stats.RefsSelection{All: true}.String(), "all")
+	t.Cmp(stats.RefsSelection{Branches: []string{"main", "feature/*"}, RemoteBranches: true}.String(), "branches=feature/*,main;remotes")
+}
+
+func TestScanRefs(tt *testing.T) {
+	t := td.NewT(tt)
+
+	repo := newTestRepository(tt)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	repo.commit("main.go", "package main", "Alice", "alice@corp.com", yesterday, "init")
+	repo.checkout("feature/login", true)
+	repo.commit("login.go", "package main", "Bob", "bob@corp.com", yesterday, "login")
+	repo.commit("logout.go", "package main", "Bob", "bob@corp.com", yesterday, "logout")
+	repo.checkout("master", false)
+
+	count := func(refs stats.RefsSelection) int {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache:         true,
+			DurationInWeeks: 2,
+			Folders:         []string{repo.path},
+			Refs:            refs,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+		total := 0
+		for _, c := range results[0].Commits {
+			total += c
+		}
+		return total
+	}
+
+	t.Cmp(count(stats.RefsSelection{}), 1)
+	t.Cmp(count(stats.RefsSelection{All: true}), 3, "commits reachable from several refs are counted once")
+	t.Cmp(count(stats.RefsSelection{Branches: []string{"feature/*"}}), 3)
+	t.Cmp(count(stats.RefsSelection{Branches: []string{"master"}}), 1)
+
+	_, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		Folders: []string{repo.path},
+		Refs:    stats.RefsSelection{Branches: []string{"["}},
+	}).Run(context.Background())
+	t.CmpError(err)
+}
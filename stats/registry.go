@@ -0,0 +1,340 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Registry is the list of the repositories to scan saved in the dot file.
+//
+// The file holds one repository path per line, optionally followed by its tags.
+// Repositories listed after a `[name]` line belong to the `name` group:
+//
+//	/home/me/dotfiles
+//	[backend]
+//	/home/me/work/api tags=go,critical
+//	/home/me/work/billing
+type Registry struct {
+	filePath     string
+	Repositories []string
+	// Groups holds the repositories of each named group
+	Groups map[string][]string
+	// Tags holds the tags of each repository
+	Tags map[string][]string
+}
+
+// registryTagsSeparator separates a repository path from its tags
+const registryTagsSeparator = " tags="
+
+// PrunedRepository is a repository removed from the registry by Prune
+type PrunedRepository struct {
+	Path   string
+	Reason string
+}
+
+// Relocation is a repository path changed by Relocate
+type Relocation struct {
+	From string
+	To   string
+}
+
+// OpenRegistry loads the registry saved in the dot file
+func OpenRegistry() (*Registry, error) {
+	filePath, err := GetDotFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadRegistry(*filePath)
+}
+
+// LoadRegistry loads the registry saved in `filePath`, creating it if not existing
+func LoadRegistry(filePath string) (*Registry, error) {
+	r := &Registry{
+		filePath: filePath,
+		Groups:   make(map[string][]string),
+		Tags:     make(map[string][]string),
+	}
+	lines, err := parseFileLinesToSlice(filePath)
+	if err != nil {
+		return nil, err
+	}
+	group := ""
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			group = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		repository := line
+		if i := strings.LastIndex(line, registryTagsSeparator); i >= 0 {
+			repository = strings.TrimSpace(line[:i])
+			r.AddTags(repository, strings.Split(line[i+len(registryTagsSeparator):], ","))
+		}
+		r.Add(repository, group)
+	}
+	return r, nil
+}
+
+// Add adds the repository to the registry, and to the `group` if not empty
+func (r *Registry) Add(repository string, group string) {
+	r.Repositories = joinSlices([]string{repository}, r.Repositories)
+	if group != "" {
+		r.Groups[group] = joinSlices([]string{repository}, r.Groups[group])
+	}
+}
+
+// AddTags adds tags to the repository
+func (r *Registry) AddTags(repository string, tags []string) {
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			r.Tags[repository] = joinSlices([]string{tag}, r.Tags[repository])
+		}
+	}
+}
+
+// GroupsOf returns the sorted names of the groups containing the repository
+func (r *Registry) GroupsOf(repository string) []string {
+	var groups []string
+	for group, repositories := range r.Groups {
+		if sliceContains(repositories, repository) {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// Select returns the repositories belonging to one of the `groups` and having one of the `tags`,
+// an empty filter selects every repository
+func (r *Registry) Select(groups []string, tags []string) []string {
+	var selected []string
+	for _, repository := range r.Repositories {
+		inGroup := len(groups) == 0
+		for _, group := range groups {
+			inGroup = inGroup || sliceContains(r.Groups[group], repository)
+		}
+		tagged := len(tags) == 0
+		for _, tag := range tags {
+			tagged = tagged || sliceContains(r.Tags[repository], tag)
+		}
+		if inGroup && tagged {
+			selected = append(selected, repository)
+		}
+	}
+	return selected
+}
+
+// Save writes the registry to its file
+func (r *Registry) Save() error {
+	var lines []string
+	grouped := make(map[string]bool)
+	var groups []string
+	for group, repositories := range r.Groups {
+		groups = append(groups, group)
+		for _, repository := range repositories {
+			grouped[repository] = true
+		}
+	}
+	sort.Strings(groups)
+
+	for _, repository := range r.Repositories {
+		if !grouped[repository] {
+			lines = append(lines, r.registryLine(repository))
+		}
+	}
+	for _, group := range groups {
+		if len(r.Groups[group]) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("[%s]", group))
+		for _, repository := range r.Groups[group] {
+			lines = append(lines, r.registryLine(repository))
+		}
+	}
+	return dumpStringsSliceToFile(lines, r.filePath)
+}
+
+// registryLine returns the line of the repository in the registry file
+func (r *Registry) registryLine(repository string) string {
+	if len(r.Tags[repository]) == 0 {
+		return repository
+	}
+	return repository + registryTagsSeparator + strings.Join(r.Tags[repository], ",")
+}
+
+// forget removes the repository from the groups and tags
+func (r *Registry) forget(repository string) {
+	for group, repositories := range r.Groups {
+		r.Groups[group] = removeFromSlice(repositories, repository)
+	}
+	delete(r.Tags, repository)
+}
+
+// rename moves the repository groups and tags to its new path
+func (r *Registry) rename(from string, to string) {
+	for group, repositories := range r.Groups {
+		var renamed []string
+		for _, repository := range repositories {
+			if repository == from {
+				repository = to
+			}
+			renamed = joinSlices([]string{repository}, renamed)
+		}
+		r.Groups[group] = renamed
+	}
+	if tags, ok := r.Tags[from]; ok {
+		delete(r.Tags, from)
+		r.AddTags(to, tags)
+	}
+}
+
+// removeFromSlice returns `slice` without `value`
+func removeFromSlice(slice []string, value string) []string {
+	var out []string
+	for _, v := range slice {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Remove removes from the registry the repository `folder` and the repositories
+// it contains, and returns the removed paths
+func (r *Registry) Remove(folder string) []string {
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		absFolder = folder
+	}
+	var removed []string
+	var kept []string
+	for _, repository := range r.Repositories {
+		if repository == folder || isSameOrSubPath(repository, absFolder) {
+			removed = append(removed, repository)
+			r.forget(repository)
+		} else {
+			kept = append(kept, repository)
+		}
+	}
+	r.Repositories = kept
+	return removed
+}
+
+// Prune removes from the registry the paths which no longer exist
+// or are no longer git repositories, and returns them
+func (r *Registry) Prune() []PrunedRepository {
+	var pruned []PrunedRepository
+	var kept []string
+	for _, repository := range r.Repositories {
+		if _, err := os.Stat(repository); err != nil {
+			reason := err.Error()
+			if errors.Is(err, os.ErrNotExist) {
+				reason = "folder does not exist"
+			}
+			pruned = append(pruned, PrunedRepository{Path: repository, Reason: reason})
+			r.forget(repository)
+			continue
+		}
+		if !isRepo(repository) {
+			pruned = append(pruned, PrunedRepository{Path: repository, Reason: "not a git repository"})
+			r.forget(repository)
+			continue
+		}
+		kept = append(kept, repository)
+	}
+	r.Repositories = kept
+	return pruned
+}
+
+// Relocate replaces the `oldPrefix` folder of the repositories paths by `newPrefix`,
+// and returns the changed paths
+func (r *Registry) Relocate(oldPrefix string, newPrefix string) []Relocation {
+	oldPrefix = filepath.Clean(oldPrefix)
+	newPrefix = filepath.Clean(newPrefix)
+	var relocations []Relocation
+	var repositories []string
+	for _, repository := range r.Repositories {
+		if isSameOrSubPath(repository, oldPrefix) {
+			relocated := newPrefix + strings.TrimPrefix(repository, oldPrefix)
+			relocations = append(relocations, Relocation{From: repository, To: relocated})
+			r.rename(repository, relocated)
+			repository = relocated
+		}
+		repositories = joinSlices([]string{repository}, repositories)
+	}
+	r.Repositories = repositories
+	return relocations
+}
+
+// isSameOrSubPath returns true if `path` is `folder` or is located in `folder`
+func isSameOrSubPath(path string, folder string) bool {
+	path = filepath.Clean(path)
+	return path == folder || strings.HasPrefix(path, strings.TrimSuffix(folder, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// RemoveRepositories removes the folders from the repositories to scan
+func RemoveRepositories(folders []string) error {
+	registry, err := OpenRegistry()
+	if err != nil {
+		return err
+	}
+	for _, folder := range folders {
+		removed := registry.Remove(folder)
+		if len(removed) == 0 {
+			fmt.Printf("Repository %s is not in the scan list\n", folder)
+		}
+		for _, repository := range removed {
+			fmt.Printf("Folder %s removed from scan list\n", repository)
+		}
+	}
+	return registry.Save()
+}
+
+// Prune removes the repositories to scan that no longer exist or are no longer
+// git repositories. With `dryRun` the repositories are only listed.
+func Prune(dryRun bool) error {
+	registry, err := OpenRegistry()
+	if err != nil {
+		return err
+	}
+	pruned := registry.Prune()
+	action := "removed from scan list"
+	if dryRun {
+		action = "would be removed from scan list"
+	}
+	for _, p := range pruned {
+		fmt.Printf("Folder %s %s: %s\n", p.Path, action, p.Reason)
+	}
+	if len(pruned) == 0 {
+		fmt.Println("Nothing to prune")
+	}
+	if dryRun {
+		return nil
+	}
+	return registry.Save()
+}
+
+// Relocate moves the repositories to scan located in `oldPrefix` to `newPrefix`
+func Relocate(oldPrefix string, newPrefix string) error {
+	registry, err := OpenRegistry()
+	if err != nil {
+		return err
+	}
+	relocations := registry.Relocate(oldPrefix, newPrefix)
+	for _, relocation := range relocations {
+		fmt.Printf("Folder %s relocated to %s\n", relocation.From, relocation.To)
+	}
+	if len(relocations) == 0 {
+		fmt.Printf("No repository located in %s\n", oldPrefix)
+	}
+	return registry.Save()
+}
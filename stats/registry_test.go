@@ -0,0 +1,123 @@
+package stats_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+// loadRegistry loads the registry saved in `filePath`, failing the test on error
+func loadRegistry(tt *testing.T, filePath string) *stats.Registry {
+	registry, err := stats.LoadRegistry(filePath)
+	if err != nil {
+		tt.Fatal(err)
+	}
+	return registry
+}
+
+func newTestRegistry(tt *testing.T, repositories ...string) (*stats.Registry, string) {
+	filePath := filepath.Join(tt.TempDir(), ".gogitstats")
+	registry := loadRegistry(tt, filePath)
+	registry.Repositories = repositories
+	if err := registry.Save(); err != nil {
+		tt.Fatal(err)
+	}
+	return loadRegistry(tt, filePath), filePath
+}
+
+func TestRegistryRemove(tt *testing.T) {
+	t := td.NewT(tt)
+	registry, _ := newTestRegistry(tt, "/work/api", "/work/front/web", "/work/front/mobile", "/work/frontend")
+
+	t.Cmp(registry.Remove("/work/front"), []string{"/work/front/web", "/work/front/mobile"})
+	t.Cmp(registry.Remove("/work/api"), []string{"/work/api"})
+	t.Cmp(registry.Remove("/unknown"), td.Nil())
+	t.Cmp(registry.Repositories, []string{"/work/frontend"})
+}
+
+func TestRegistryPrune(tt *testing.T) {
+	t := td.NewT(tt)
+	repo := newTestRepository(tt)
+	notARepo := tt.TempDir()
+	missing := filepath.Join(notARepo, "missing")
+	registry, filePath := newTestRegistry(tt, repo.path, notARepo, missing)
+
+	pruned := registry.Prune()
+	t.Cmp(pruned, []stats.PrunedRepository{
+		{Path: notARepo, Reason: "not a git repository"},
+		{Path: missing, Reason: "folder does not exist"},
+	})
+	t.Cmp(registry.Repositories, []string{repo.path})
+
+	// the file is only changed on save, allowing dry runs
+	t.Cmp(loadRegistry(tt, filePath).Repositories, td.Len(3))
+	t.CmpNoError(registry.Save())
+	t.Cmp(loadRegistry(tt, filePath).Repositories, []string{repo.path})
+}
+
+func TestRegistryRelocate(tt *testing.T) {
+	t := td.NewT(tt)
+	registry, _ := newTestRegistry(tt, "/old/api", "/old/web", "/older/lib", "/new/api")
+
+	t.Cmp(registry.Relocate("/old/", "/new"), []stats.Relocation{
+		{From: "/old/api", To: "/new/api"},
+		{From: "/old/web", To: "/new/web"},
+	})
+	t.Cmp(registry.Repositories, []string{"/new/api", "/new/web", "/older/lib"})
+}
+
+func TestRegistryGroups(tt *testing.T) {
+	t := td.NewT(tt)
+	filePath := filepath.Join(tt.TempDir(), ".gogitstats")
+	content := "/home/dotfiles\n\n# work repositories\n[backend]\n/work/api tags=go, critical\n/work/billing\n[mobile]\n/work/app tags=kotlin\n/work/api\n"
+	t.CmpNoError(os.WriteFile(filePath, []byte(content), 0644))
+
+	registry := loadRegistry(tt, filePath)
+	t.Cmp(registry.Repositories, []string{"/home/dotfiles", "/work/api", "/work/billing", "/work/app"})
+	t.Cmp(registry.Groups, map[string][]string{
+		"backend": {"/work/api", "/work/billing"},
+		"mobile":  {"/work/app", "/work/api"},
+	})
+	t.Cmp(registry.Tags, map[string][]string{
+		"/work/api": {"go", "critical"},
+		"/work/app": {"kotlin"},
+	})
+	t.Cmp(registry.GroupsOf("/work/api"), []string{"backend", "mobile"})
+
+	t.Cmp(registry.Select(nil, nil), registry.Repositories)
+	t.Cmp(registry.Select([]string{"backend"}, nil), []string{"/work/api", "/work/billing"})
+	t.Cmp(registry.Select([]string{"mobile"}, []string{"go"}), []string{"/work/api"})
+	t.Cmp(registry.Select(nil, []string{"kotlin", "critical"}), []string{"/work/api", "/work/app"})
+	t.Cmp(registry.Select([]string{"unknown"}, nil), td.Nil())
+
+	t.CmpNoError(registry.Save())
+	t.Cmp(loadRegistry(tt, filePath), registry)
+
+	registry.Relocate("/work", "/projects")
+	registry.Remove("/projects/billing")
+	t.Cmp(registry.Groups, map[string][]string{
+		"backend": {"/projects/api"},
+		"mobile":  {"/projects/app", "/projects/api"},
+	})
+	t.Cmp(registry.Tags["/projects/api"], []string{"go", "critical"})
+	t.Cmp(registry.Tags, td.Not(td.ContainsKey("/work/api")))
+}
+
+func TestRegistryBackwardCompatible(tt *testing.T) {
+	t := td.NewT(tt)
+	filePath := filepath.Join(tt.TempDir(), ".gogitstats")
+	content := "/work/api\n/work/web"
+	t.CmpNoError(os.WriteFile(filePath, []byte(content), 0644))
+
+	registry := loadRegistry(tt, filePath)
+	t.Cmp(registry.Repositories, []string{"/work/api", "/work/web"})
+	t.Cmp(registry.Groups, td.Empty())
+
+	t.CmpNoError(registry.Save())
+	saved, err := os.ReadFile(filePath)
+	t.CmpNoError(err)
+	t.Cmp(string(saved), content)
+}
@@ -2,9 +2,8 @@ package stats
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
-	"io"
-	"log"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -13,12 +12,11 @@ import (
 
 // GetFolders returns all the folders needs to be scanned saved in dotfile
 func GetFolders() ([]string, error) {
-	var repos []string
-	filePath, err := GetDotFilePath()
+	registry, err := OpenRegistry()
 	if err != nil {
 		return []string{}, err
 	}
-	repos = parseFileLinesToSlice(*filePath)
+	repos := registry.Repositories
 	if len(repos) == 0 || isRepo(".") {
 		repos = []string{"."}
 	}
@@ -26,13 +24,26 @@ func GetFolders() ([]string, error) {
 	return repos, nil
 }
 
+// GetGroupFolders returns the folders saved in dotfile belonging to one of the `groups`
+// and having one of the `tags`
+func GetGroupFolders(groups []string, tags []string) ([]string, error) {
+	registry, err := OpenRegistry()
+	if err != nil {
+		return []string{}, err
+	}
+	repos := registry.Select(groups, tags)
+	if len(repos) == 0 {
+		return []string{}, fmt.Errorf("no repository in groups [%s] with tags [%s]", strings.Join(groups, ","), strings.Join(tags, ","))
+	}
+	return repos, nil
+}
+
 // GetDotFilePath returns the dot file for the repos list.
 // Creates it and the enclosing folder if it does not exist.
 func GetDotFilePath() (*string, error) {
 	usr, err := user.Current()
 	if err != nil {
-		log.Fatal(err)
-		return nil, err
+		return nil, fmt.Errorf("cannot find the user home folder: %w", err)
 	}
 
 	dotFile := usr.HomeDir + "/.gogitstats"
@@ -41,32 +52,21 @@ func GetDotFilePath() (*string, error) {
 }
 
 // openFile opens the file located at `filePath`. Creates it if not existing.
-func openFile(filePath string) *os.File {
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_RDWR, 0755)
+func openFile(filePath string) (*os.File, error) {
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0755)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// file does not exist
-			_, err = os.Create(filePath)
-			if err != nil {
-				panic(err)
-			}
-			f, err = os.OpenFile(filePath, os.O_APPEND|os.O_RDWR, 0755)
-			if err != nil {
-				panic(err)
-			}
-		} else {
-			// other error
-			panic(err)
-		}
+		return nil, fmt.Errorf("cannot open file %s: %w", filePath, err)
 	}
-
-	return f
+	return f, nil
 }
 
 // parseFileLinesToSlice given a file path string, gets the content
 // of each line and parses it to a slice of strings.
-func parseFileLinesToSlice(configFilePath string) []string {
-	f := openFile(configFilePath)
+func parseFileLinesToSlice(configFilePath string) ([]string, error) {
+	f, err := openFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
 	defer f.Close()
 
 	var lines []string
@@ -75,12 +75,10 @@ func parseFileLinesToSlice(configFilePath string) []string {
 		lines = append(lines, scanner.Text())
 	}
 	if err := scanner.Err(); err != nil {
-		if err != io.EOF {
-			panic(err)
-		}
+		return nil, fmt.Errorf("cannot read file %s: %w", configFilePath, err)
 	}
 
-	return lines
+	return lines, nil
 }
 
 // sliceContains returns true if `slice` contains `value`
@@ -110,43 +108,47 @@ func dumpStringsSliceToFile(repos []string, filePath string) error {
 	return os.WriteFile(filePath, []byte(content), 0755)
 }
 
-// addNewSliceElementsToFile given a slice of strings representing paths, stores them
-// to the filesystem
-func addNewSliceElementsToFile(filePath string, newRepos []string) error {
-	existingRepos := parseFileLinesToSlice(filePath)
-	repos := joinSlices(newRepos, existingRepos)
-	return dumpStringsSliceToFile(repos, filePath)
-}
-
 // recursiveScanFolder starts the recursive search of git repositories
 // living in the `folder` subtree
 func recursiveScanFolder(folder string) ([]string, error) {
 	return ScanGitFolders(make([]string, 0), folder)
 }
 
-// Scan scans a new folder for Git repositories
-func Scan(folder string) error {
-	repositories, err := recursiveScanFolder(folder)
-	if err != nil {
-		return err
+// Scan scans a new folder for Git repositories, and adds them to the `group`
+// with the `tags` if not empty
+func Scan(folder string, group string, tags []string) error {
+	repositories, scanErr := recursiveScanFolder(folder)
+	if len(repositories) == 0 {
+		return scanErr
 	}
-	filePath, err := GetDotFilePath()
+	registry, err := OpenRegistry()
 	if err != nil {
 		return err
 	}
-	return addNewSliceElementsToFile(*filePath, repositories)
+	for _, repository := range repositories {
+		registry.Add(repository, group)
+		registry.AddTags(repository, tags)
+	}
+	// the repositories found are saved even if some subfolders could not be scanned
+	return errors.Join(registry.Save(), scanErr)
 }
 
-// List list all repositories wich saved to scan
+// List list all repositories wich saved to scan, with their groups and tags
 func List() error {
-	configFilePath, err := GetDotFilePath()
+	registry, err := OpenRegistry()
 	if err != nil {
 		return err
 	}
-	repositories := parseFileLinesToSlice(*configFilePath)
 	fmt.Printf("Git folders:\n\n")
-	for _, repository := range repositories {
-		fmt.Printf("- %s\n", repository)
+	for _, repository := range registry.Repositories {
+		line := "- " + repository
+		if groups := registry.GroupsOf(repository); len(groups) > 0 {
+			line += fmt.Sprintf(" [%s]", strings.Join(groups, ", "))
+		}
+		if tags := registry.Tags[repository]; len(tags) > 0 {
+			line += fmt.Sprintf(" (tags: %s)", strings.Join(tags, ", "))
+		}
+		fmt.Println(line)
 	}
 	return nil
 }
@@ -158,28 +160,29 @@ func ShouldBeIgnored(folderName string) bool {
 // ScanGitFolders returns a list of subfolders of `folder` ending with `.git`.
 // Returns the base folder of the repo, the .git folder parent.
 // Recursively searches in the subfolders by passing an existing `folders` slice.
+// The subfolders which cannot be read are skipped, their errors are returned
+// with the repositories found in the other ones.
 func ScanGitFolders(folders []string, folder string) ([]string, error) {
 	// trim the last `/`
 	folder = strings.TrimSuffix(folder, "/")
 
 	f, err := os.Open(folder)
 	if err != nil {
-		log.Fatal(err)
-		return folders, err
+		return folders, fmt.Errorf("cannot scan folder: %w", err)
 	}
 
 	pathFrom, err := os.Getwd()
 	if err != nil {
-		log.Fatal(err)
-		return folders, err
+		f.Close()
+		return folders, fmt.Errorf("cannot scan folder %s: %w", folder, err)
 	}
 	pathFrom = filepath.Join(pathFrom, folder)
 	files, err := f.Readdir(-1)
 	f.Close()
 	if err != nil {
-		log.Fatal(err)
-		return folders, err
+		return folders, fmt.Errorf("cannot scan folder %s: %w", folder, err)
 	}
+	var errs []error
 	for _, file := range files {
 		if file.IsDir() {
 			pathRelative := folder + "/" + file.Name()
@@ -195,10 +198,11 @@ func ScanGitFolders(folders []string, folder string) ([]string, error) {
 			}
 			folders, err = ScanGitFolders(folders, pathRelative)
 			if err != nil {
-				return folders, err
+				// continue with the other subfolders
+				errs = append(errs, err)
 			}
 		}
 	}
 
-	return folders, nil
+	return folders, errors.Join(errs...)
 }
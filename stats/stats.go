@@ -1,22 +1,21 @@
 package stats
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
-	"regexp"
+	"math"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/schollz/progressbar/v3"
 )
 
-const outOfRange = 99999
-
 var DefaultDurationInDays = 365
 
 type LaunchOptions struct {
@@ -26,8 +25,31 @@ type LaunchOptions struct {
 	Merge            bool
 	Delta            string
 	Dashboard        bool
+	Output           OutputType
 	PatternToExclude []string
 	PatternToInclude []string
+	MailmapFile      string
+	NoCache          bool
+	CacheDir         string
+	CoAuthorCredit   CoAuthorCredit
+	Refs             RefsSelection
+	Since            string
+	Until            string
+	WeekStart        WeekStart
+	Locale           string
+	Timezone         string
+	DateSource       DateSource
+	MatchCommitter   bool
+	Merges           MergesPolicy
+	FirstParent      bool
+	MergeLines       MergeLines
+	Theme            string
+	Scale            Scale
+	Jobs             int
+	FailOnError      bool
+	ByAuthor         bool
+	TopAuthors       int
+	Languages        bool
 }
 
 type StatsResult struct {
@@ -40,7 +62,18 @@ type StatsResult struct {
 	HoursCommits    [24]int
 	DayCommits      [7]int
 	AuthorsEditions map[string]map[string]int
-	Error           error
+	AuthorsCommits  map[string]map[int]int
+	AuthorsFiles    map[string]map[string]bool
+	Additions       int
+	Deletions       int
+	// Languages holds the editions per language, also per author and per repository
+	Languages             Languages
+	AuthorsLanguages      map[string]Languages
+	RepositoriesLanguages map[string]Languages
+	// RequestedAuthors holds the contributors designated by the users filter,
+	// the other ones are only co-authors of their commits
+	RequestedAuthors map[string]bool
+	Error            error
 }
 
 type StatsOptions struct {
@@ -51,6 +84,23 @@ type StatsOptions struct {
 	Silent               bool
 	PatternToExclude     []string
 	PatternToInclude     []string
+	MailmapFile          string
+	NoCache              bool
+	CacheDir             string
+	CoAuthorCredit       CoAuthorCredit
+	Refs                 RefsSelection
+	Since                string
+	Until                string
+	WeekStart            WeekStart
+	Locale               string
+	Timezone             string
+	DateSource           DateSource
+	MatchCommitter       bool
+	Merges               MergesPolicy
+	FirstParent          bool
+	MergeLines           MergeLines
+	Theme                string
+	Scale                Scale
 }
 
 func isRepo(path string) bool {
@@ -58,66 +108,83 @@ func isRepo(path string) bool {
 	return err == nil
 }
 
-// TODO use an interface object in order to refacto in same place the statistic run logic and then print results
-
+// Launch computes the statistics of the folders displaying a progress bar,
+// then prints them unless they are rendered by the dashboard
 func Launch(opts LaunchOptions) []*StatsResult {
-	var results []*StatsResult = []*StatsResult{}
-	var wg sync.WaitGroup
-	bar := progressbar.Default(-1, "Analyzing commits")
+	results, err := analyzeWithProgress(opts)
+	if err != nil {
+		results = []*StatsResult{{Options: StatsOptions{Folders: opts.Folders}, Folder: strings.Join(opts.Folders, ","), Error: err}}
+	}
+	if err := printResults(opts, results); err != nil {
+		Print(Error, fmt.Sprintf("Cannot print results: %s\n", err))
+	}
+	PrintFailures(os.Stderr, Failures(results))
 
-	if opts.Merge {
-		options := StatsOptions{
-			EmailOrUsername:      opts.User,
-			DurationParamInWeeks: opts.DurationInWeeks,
-			Folders:              opts.Folders,
-			Delta:                opts.Delta,
-			Silent:               opts.Dashboard,
-			PatternToExclude:     opts.PatternToExclude,
-			PatternToInclude:     opts.PatternToInclude,
-		}
+	return results
+}
 
-		r := &StatsResult{
-			Options: options,
-		}
-		populateDurationInDays(opts, r)
-
-		results = append(results, r)
-		wg.Add(1)
-		go Stats(r, &wg, bar)
-	} else {
-		for _, folder := range opts.Folders {
-			options := StatsOptions{
-				EmailOrUsername:      opts.User,
-				DurationParamInWeeks: opts.DurationInWeeks,
-				Folders:              []string{folder},
-				Delta:                opts.Delta,
-				Silent:               opts.Dashboard,
-				PatternToExclude:     opts.PatternToExclude,
-				PatternToInclude:     opts.PatternToInclude,
-			}
-			r := &StatsResult{
-				Options: options,
-			}
-			populateDurationInDays(opts, r)
-			results = append(results, r)
-			wg.Add(1)
-			go Stats(r, &wg, bar)
-		}
+// PrintStats computes the statistics of the folders displaying a progress bar, then
+// prints them followed by the repositories which could not be analyzed
+func PrintStats(opts LaunchOptions) error {
+	results, err := analyzeWithProgress(opts)
+	if err != nil {
+		return err
 	}
-	wg.Wait()
+	if err := printResults(opts, results); err != nil {
+		return err
+	}
+	return reportFailures(opts, results)
+}
 
-	for _, r := range results {
-		if !opts.Dashboard {
+// printResults prints the results unless they are rendered by the dashboard, the errors
+// are left to the failures summary
+func printResults(opts LaunchOptions, results []*StatsResult) error {
+	switch {
+	case opts.Dashboard:
+		// printed by the dashboard
+	case opts.Output == JSON:
+		return PrintJSON(os.Stdout, results)
+	default:
+		for _, r := range results {
+			if !r.analyzed() {
+				// only listed in the failures
+				continue
+			}
 			fmt.Println()
-			PrintResult(r)
+			if opts.ByAuthor {
+				PrintAuthors(r, opts.TopAuthors)
+			} else {
+				PrintResult(r)
+			}
+			if opts.Languages {
+				fmt.Println()
+				if err := PrintLanguages(os.Stdout, r, opts.TopAuthors); err != nil {
+					return err
+				}
+			}
 		}
 	}
+	return nil
+}
 
-	return results
+// analyzeWithProgress runs the analyzer displaying a progress bar on the standard error,
+// an error is only returned on invalid options
+func analyzeWithProgress(opts LaunchOptions) ([]*StatsResult, error) {
+	bar := progressbar.Default(-1, "Analyzing commits")
+	analyzer := NewAnalyzer(opts)
+	analyzer.OnCommit = func() {
+		_ = bar.Add(1)
+	}
+	return analyzer.Run(context.Background())
 }
 
-func populateDurationInDays(options LaunchOptions, r *StatsResult) {
-	nowDate := time.Now()
+// populateDurationInDays sets the scan window of the result from the options,
+// an error is returned if they do not describe a valid window
+func populateDurationInDays(options LaunchOptions, r *StatsResult) error {
+	if options.Since != "" || options.Until != "" {
+		return populateDateRange(options, r)
+	}
+	nowDate := scanNow(options.Timezone)
 	end := nowDate
 
 	delta := options.Delta
@@ -125,8 +192,7 @@ func populateDurationInDays(options LaunchOptions, r *StatsResult) {
 	case strings.Contains(delta, "y"):
 		value, err := strconv.Atoi(strings.Split(delta, "y")[0])
 		if err != nil {
-			r.Error = errors.New("error delta is not a number")
-			return
+			return errors.New("error delta is not a number")
 		}
 		if value > 0 {
 			value = -value
@@ -135,8 +201,7 @@ func populateDurationInDays(options LaunchOptions, r *StatsResult) {
 	case strings.Contains(delta, "m"):
 		value, err := strconv.Atoi(strings.Split(delta, "m")[0])
 		if err != nil {
-			r.Error = errors.New("error delta is not a number")
-			return
+			return errors.New("error delta is not a number")
 		}
 		if value > 0 {
 			value = -value
@@ -145,8 +210,7 @@ func populateDurationInDays(options LaunchOptions, r *StatsResult) {
 	case strings.Contains(delta, "w"):
 		value, err := strconv.Atoi(strings.Split(delta, "w")[0])
 		if err != nil {
-			r.Error = errors.New("error delta is not a number")
-			return
+			return errors.New("error delta is not a number")
 		}
 		if value > 0 {
 			value = -value
@@ -155,8 +219,7 @@ func populateDurationInDays(options LaunchOptions, r *StatsResult) {
 	case strings.Contains(delta, "d"):
 		value, err := strconv.Atoi(strings.Split(delta, "d")[0])
 		if err != nil {
-			r.Error = errors.New("error delta is not a number")
-			return
+			return errors.New("error delta is not a number")
 		}
 		if value > 0 {
 			value = -value
@@ -164,8 +227,7 @@ func populateDurationInDays(options LaunchOptions, r *StatsResult) {
 		end = nowDate.AddDate(0, 0, value)
 	default:
 		if delta != "" {
-			r.Error = errors.New("invalid delta value use the format: <int>[y/m/w/d]")
-			return
+			return errors.New("invalid delta value use the format: <int>[y/m/w/d]")
 		}
 	}
 	durationInDays := DefaultDurationInDays
@@ -175,33 +237,30 @@ func populateDurationInDays(options LaunchOptions, r *StatsResult) {
 	r.DurationInDays = durationInDays
 	r.EndOfScan = end
 	r.BeginOfScan = end.AddDate(0, 0, -durationInDays)
-	if int(r.BeginOfScan.Weekday()) != 1 {
-		// Not a monday
-		// offset := math.Max(0, float64(6-int(r.BeginOfScan.Weekday())))
-		offset := -1 * (int(r.BeginOfScan.Weekday()) - 1)
+	if r.BeginOfScan.Weekday() != options.WeekStart.Weekday() {
+		// Not the first day of a week
+		offset := -((int(r.BeginOfScan.Weekday()) - int(options.WeekStart.Weekday()) + 7) % 7)
 		r.BeginOfScan = getBeginningOfDay(r.BeginOfScan.AddDate(0, 0, offset))
 
 		r.EndOfScan = getEndOfDay(r.EndOfScan.AddDate(0, 0, offset+6))
 		daysBetween := r.EndOfScan.Sub(r.BeginOfScan).Hours() / 24
 		r.DurationInDays = int(daysBetween)
 	}
+	return nil
 }
 
 func daysBetween(begin time.Time, end time.Time) int {
 	return int(end.Sub(begin).Hours() / 24)
 }
 
-// Stats calculates and prints the stats.
+// Stats calculates the stats.
+//
+// Deprecated: use an Analyzer.
 func Stats(r *StatsResult, wg *sync.WaitGroup, bar *progressbar.ProgressBar) {
 	defer wg.Done()
-	err := processRepositories(r, bar)
-
-	if err != nil {
-		r.Error = err
-		return
-	}
-
-	r.Folder = strings.Join(r.Options.Folders, ",")
+	analyze(context.Background(), r, nil, func() {
+		_ = bar.Add(1)
+	})
 }
 
 // getBeginningOfDay given a time.Time calculates the start time of that day
@@ -218,145 +277,217 @@ func getEndOfDay(t time.Time) time.Time {
 	return startOfDay
 }
 
-// countDaysSinceDate counts how many days passed since the passed `date`
-func countDaysSinceDate(date time.Time, r *StatsResult) int {
-	days := 0
-	endDate := getEndOfDay(r.EndOfScan)
-	if !date.Before(endDate) && !date.Equal(endDate) {
-		return outOfRange
-	} else if date.Equal(endDate) {
-		return days
-	}
-	for date.Before(endDate) {
-		date = date.Add(time.Hour * 24)
-		days++
-		if days > r.DurationInDays {
-			return outOfRange
-		}
-	}
-	return days
+// commitsKey returns the key of the `Commits` map holding the commits of
+// the day `date`, as computed by fillCommits
+func (r *StatsResult) commitsKey(date time.Time) int {
+	end := getBeginningOfDay(r.EndOfScan)
+	days := int(math.Round(end.Sub(getBeginningOfDay(date)).Hours() / 24))
+	return days + 1 + calcOffset(r.EndOfScan)
+}
+
+// CommitsOn returns the number of commits of the day `date`
+func (r *StatsResult) CommitsOn(date time.Time) int {
+	return r.Commits[r.commitsKey(date)]
+}
+
+// inScan returns true if `date` is in the scan window
+func (r *StatsResult) inScan(date time.Time) bool {
+	return !date.Before(getBeginningOfDay(r.BeginOfScan)) && !date.After(getEndOfDay(r.EndOfScan))
+}
+
+// scanDays returns the number of days of the scan window, first and last days included
+func (r *StatsResult) scanDays() int {
+	begin := getBeginningOfDay(r.BeginOfScan)
+	end := getBeginningOfDay(r.EndOfScan)
+	return int(math.Round(end.Sub(begin).Hours()/24)) + 1
+}
+
+// gridBegin returns the first day of the heatmap grid: the first day
+// of the week containing the beginning of the scan
+func (r *StatsResult) gridBegin() time.Time {
+	return r.Options.WeekStart.startOfWeek(r.BeginOfScan)
+}
+
+// scanWeeks returns the number of weeks columns of the scan window
+func (r *StatsResult) scanWeeks() int {
+	gap := int(math.Round(getBeginningOfDay(r.BeginOfScan).Sub(r.gridBegin()).Hours() / 24))
+	return (gap + r.scanDays() + 6) / 7
 }
 
 // fillCommits given a repository found in `path`, gets the commits and
 // puts them in the `commits` map, returning it when completed
-func fillCommits(r *StatsResult, emailOrUsername *string, path string, bar *progressbar.ProgressBar) error {
+func fillCommits(ctx context.Context, r *StatsResult, emailOrUsername *string, path string, patterns *filePatterns, onCommit func()) error {
 	// instantiate a git repo object from path
 	repo, err := git.PlainOpen(path)
 	if err != nil {
-		// log.Fatalf("Cannot get stat from folder (not a repository): %s", path)
-		return fmt.Errorf("cannot get stat from folder (not a repository): %s", path)
+		return ErrNotRepository
+	}
+	mailmap, err := loadMailmap(repo, path, r.Options.MailmapFile)
+	if err != nil {
+		return fmt.Errorf("cannot read mailmap: %s", err)
+	}
+	cache := openCommitCache(path, r.Options.Refs, r.Options.CacheDir, r.Options.NoCache)
+	h := &history{
+		repo:        repo,
+		refs:        r.Options.Refs,
+		cache:       cache,
+		dateSource:  r.Options.DateSource,
+		firstParent: r.Options.FirstParent,
 	}
-	// Remove one day to end date to be sure parse today date
-	// trueEndDateParse := endDate.AddDate(0, 0, 1)
-	// get the commits history until endDate is not reached
-	iterator, err := repo.Log(&git.LogOptions{Since: &r.BeginOfScan, Until: &r.EndOfScan})
+	// iterate the commits, only the ones missing from the cache are read from the git history
+	location, err := loadTimezone(r.Options.Timezone)
 	if err != nil {
-		log.Fatalf("Cannot get repository history: %s", err)
 		return err
 	}
-	// iterate the commits
-	offset := calcOffset(r.EndOfScan)
-	err = iterator.ForEach(func(c *object.Commit) error {
-		daysAgo := countDaysSinceDate(c.Author.When, r) + offset
-		hour := c.Author.When.Hour()
-		day := int(c.Author.When.Weekday())
-		if daysAgo == outOfRange {
+	err = h.forEach(r.BeginOfScan, r.EndOfScan, func(c *CommitRecord) error {
+		when := inTimezone(c.date(r.Options.DateSource), location)
+		if !r.inScan(when) || !r.Options.Merges.selects(c) {
 			return nil
 		}
+		hour := when.Hour()
+		day := int(when.Weekday())
 
+		var users []string
 		if emailOrUsername != nil {
-			users := strings.Split(*emailOrUsername, ",")
-			var found bool
-			for _, u := range users {
-				if strings.Contains(u, "@") && c.Author.Email == u {
-					found = true
-					break
-				} else if c.Author.Name == u {
-					found = true
-					break
-				}
-			}
-			if !found {
+			users = strings.Split(*emailOrUsername, ",")
+			matchCommitter := r.Options.MatchCommitter && matchUser(users, mailmap, c.CommitterName, c.CommitterEmail)
+			if !matchCommitter && !c.matchContributor(users, mailmap, r.Options.CoAuthorCredit) {
 				return nil
 			}
 		}
+		contributors := c.contributors(mailmap, r.Options.CoAuthorCredit)
 
-		// TODO find a solution for improve perf
-		stats, _ := c.Stats()
-		ignore := false
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var stats []FileStat
+		countsLines := !c.isMerge() || r.Options.countsMergeLines()
+		if countsLines || !patterns.empty() {
+			// merge commits are compared with their first parent
+			var err error
+			stats, err = h.stats(ctx, c)
+			if err != nil {
+				return fmt.Errorf("cannot compute the changes of commit %s: %w", c.Hash, err)
+			}
+		}
+		additions := 0
+		deletions := 0
+		edited := false
+		var files []string
+		languages := Languages{}
 		for _, stat := range stats {
-			for _, pattern := range r.Options.PatternToExclude {
-				pR, eRegex := regexp.Compile(pattern)
-				if eRegex != nil {
-					log.Fatalf("Input regex is not valid")
+			if patterns.ignore(stat.Name) {
+				continue
+			}
+			edited = true
+			files = append(files, stat.Name)
+			languages.addFile(stat.Name, stat.Additions, stat.Deletions)
+			additions += stat.Additions
+			deletions += stat.Deletions
+		}
+		if !edited && !patterns.empty() {
+			// the commit only changes filtered out files
+			return nil
+		}
+		if edited && countsLines {
+			r.Additions += additions
+			r.Deletions += deletions
+			creditedAdditions := creditedLines(additions, len(contributors), r.Options.CoAuthorCredit)
+			creditedDeletions := creditedLines(deletions, len(contributors), r.Options.CoAuthorCredit)
+			creditedLanguages := languages.credit(len(contributors), r.Options.CoAuthorCredit)
+			r.Languages.addCommit(languages)
+			if r.RepositoriesLanguages[path] == nil {
+				r.RepositoriesLanguages[path] = Languages{}
+			}
+			r.RepositoriesLanguages[path].addCommit(languages)
+			for i, contributor := range contributors {
+				if r.AuthorsLanguages[contributor] == nil {
+					r.AuthorsLanguages[contributor] = Languages{}
 				}
-				if pR.MatchString(stat.Name) {
-					ignore = true
-					break
+				r.AuthorsLanguages[contributor].addCommit(creditedLanguages[i])
+				if r.AuthorsFiles[contributor] == nil {
+					r.AuthorsFiles[contributor] = make(map[string]bool)
 				}
-			}
-			for _, pattern := range r.Options.PatternToInclude {
-				pR, eRegex := regexp.Compile(pattern)
-				if eRegex != nil {
-					log.Fatalf("Input regex is not valid")
+				for _, file := range files {
+					// the same path in two repositories is not the same file
+					r.AuthorsFiles[contributor][filepath.Join(path, file)] = true
 				}
-				if !pR.MatchString(stat.Name) {
-					ignore = true
-					continue
-				} else {
-					ignore = false
-					break
+				if r.AuthorsEditions[contributor] == nil {
+					r.AuthorsEditions[contributor] = make(map[string]int, 2)
 				}
+				r.AuthorsEditions[contributor]["additions"] = r.AuthorsEditions[contributor]["additions"] + creditedAdditions[i]
+				r.AuthorsEditions[contributor]["deletions"] = r.AuthorsEditions[contributor]["deletions"] + creditedDeletions[i]
 			}
+		}
 
-			if ignore {
-				continue
-			}
-			if r.AuthorsEditions[c.Author.Name] == nil {
-				r.AuthorsEditions[c.Author.Name] = make(map[string]int, 2)
+		key := r.commitsKey(when)
+		r.Commits[key] = r.Commits[key] + 1
+		for _, contributor := range contributors {
+			if r.AuthorsCommits[contributor] == nil {
+				r.AuthorsCommits[contributor] = make(map[int]int)
 			}
-			r.AuthorsEditions[c.Author.Name]["additions"] = r.AuthorsEditions[c.Author.Name]["additions"] + stat.Addition
-			r.AuthorsEditions[c.Author.Name]["deletions"] = r.AuthorsEditions[c.Author.Name]["deletions"] + stat.Deletion
+			r.AuthorsCommits[contributor][key] = r.AuthorsCommits[contributor][key] + 1
 		}
-
-		if daysAgo <= r.DurationInDays {
-			r.Commits[daysAgo] = r.Commits[daysAgo] + 1
-			r.HoursCommits[hour] = r.HoursCommits[hour] + 1
-			r.DayCommits[day] = r.DayCommits[day] + 1
+		for _, contributor := range c.requestedContributors(users, mailmap, r.Options.CoAuthorCredit) {
+			r.RequestedAuthors[contributor] = true
 		}
-		_ = bar.Add(1)
+		r.HoursCommits[hour] = r.HoursCommits[hour] + 1
+		r.DayCommits[day] = r.DayCommits[day] + 1
+		onCommit()
 		return nil
 	})
 	if err != nil {
-		log.Fatalf("Error on git-log iterate: %s", err)
-		return err
+		return fmt.Errorf("cannot read repository history: %w", err)
 	}
 
+	// the cache is only an optimization, statistics are valid even if it cannot be saved
+	_ = cache.save()
 	return nil
 }
 
+// matchUser returns true if one of the `users` (emails or names) designates the
+// commit identity, either as recorded in the commit or as resolved by the mailmap
+func matchUser(users []string, mailmap *Mailmap, name string, email string) bool {
+	canonicalName, canonicalEmail := mailmap.Resolve(name, email)
+	for _, u := range users {
+		if strings.Contains(u, "@") {
+			_, canonicalUser := mailmap.Resolve("", u)
+			if email == u || strings.EqualFold(canonicalEmail, canonicalUser) {
+				return true
+			}
+		}
+		if name == u || canonicalName == u {
+			return true
+		}
+	}
+	return false
+}
+
 // processRepositories given an user email, returns the
 // commits made in the last 6 months
-func processRepositories(r *StatsResult, bar *progressbar.ProgressBar) error {
-	daysInMap := r.DurationInDays
-
-	r.Commits = make(map[int]int, daysInMap)
+func processRepositories(ctx context.Context, r *StatsResult, patterns *filePatterns, onCommit func()) error {
+	r.Commits = make(map[int]int, r.scanDays())
 	r.AuthorsEditions = make(map[string]map[string]int)
-	var errReturn error
-	for i := daysInMap; i > 0; i-- {
-		r.Commits[i] = 0
+	r.AuthorsCommits = make(map[string]map[int]int)
+	r.AuthorsFiles = make(map[string]map[string]bool)
+	r.Languages = Languages{}
+	r.AuthorsLanguages = make(map[string]Languages)
+	r.RepositoriesLanguages = make(map[string]Languages)
+	r.RequestedAuthors = make(map[string]bool)
+	var errs []error
+	for day := getBeginningOfDay(r.BeginOfScan); r.inScan(day); day = day.AddDate(0, 0, 1) {
+		r.Commits[r.commitsKey(day)] = 0
 	}
 
 	for _, path := range r.Options.Folders {
-		err := fillCommits(r, r.Options.EmailOrUsername, path, bar)
+		err := fillCommits(ctx, r, r.Options.EmailOrUsername, path, patterns, onCommit)
 		if err != nil {
 			// continue for other folders
-			Print(Error, fmt.Sprintf("\nError scanning folder repository %s: %s\n", path, err))
-			errReturn = err
+			errs = append(errs, &RepositoryError{Path: path, Err: err})
 			continue
 		}
 	}
-	return errReturn
+	return errors.Join(errs...)
 }
 
 // calcOffset determines and returns the amount of days missing to fill
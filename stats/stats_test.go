@@ -22,6 +22,7 @@ var days [7]int
 var statResults []stats.StatsResult = []stats.StatsResult{
 	stats.StatsResult{
 		Options: stats.StatsOptions{
+			NoCache:              true,
 			EmailOrUsername:      nil,
 			DurationParamInWeeks: 4,
 			Folders:              currentRepo,
@@ -38,6 +39,7 @@ var statResults []stats.StatsResult = []stats.StatsResult{
 	},
 	stats.StatsResult{
 		Options: stats.StatsOptions{
+			NoCache:              true,
 			EmailOrUsername:      &unknownUser,
 			DurationParamInWeeks: 4,
 			Folders:              currentRepo,
@@ -54,6 +56,7 @@ var statResults []stats.StatsResult = []stats.StatsResult{
 	},
 	stats.StatsResult{
 		Options: stats.StatsOptions{
+			NoCache:              true,
 			EmailOrUsername:      &existingUser,
 			DurationParamInWeeks: 4,
 			Folders:              currentRepo,
@@ -85,6 +88,7 @@ func TestStatWithDelta(tt *testing.T) {
 
 	for _, d := range []string{"1w", "1m", "1y", "2y"} {
 		options := stats.LaunchOptions{
+			NoCache:         true,
 			User:            nil,
 			DurationInWeeks: 4,
 			Folders:         currentRepo,
@@ -97,6 +101,7 @@ func TestStatWithDelta(tt *testing.T) {
 	}
 
 	options := stats.LaunchOptions{
+		NoCache:         true,
 		User:            nil,
 		DurationInWeeks: 4,
 		Folders:         currentRepo,
@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"sort"
+)
+
+// RepositoryCommits is the number of commits found in a repository
+type RepositoryCommits struct {
+	Folder  string
+	Commits int
+}
+
+// Summary holds the statistics of several results aggregated together,
+// as displayed by the dashboard and the reports
+type Summary struct {
+	// Merged holds the commits of all the results per day
+	Merged       StatsResult
+	Commits      int
+	Analyzed     int
+	Errors       int
+	HoursCommits [24]int
+	// DayCommits holds the commits per weekday, index 0 is Sunday
+	DayCommits   [7]int
+	Contributors []Contributions
+	Repositories []RepositoryCommits
+	// Languages holds the editions per language of all the results
+	Languages Languages
+}
+
+// Summarize aggregates the results, ignoring the ones in error.
+// Contributors are sorted by decreasing additions + deletions.
+func Summarize(results []*StatsResult) Summary {
+	s := Summary{Languages: Languages{}}
+	if len(results) == 0 {
+		return s
+	}
+	s.Merged = StatsResult{
+		Options:        results[0].Options,
+		BeginOfScan:    results[0].BeginOfScan,
+		EndOfScan:      results[0].EndOfScan,
+		DurationInDays: results[0].DurationInDays,
+		Folder:         "",
+		Commits:        make(map[int]int),
+		Error:          nil,
+	}
+
+	authors := make(map[string]*Contributions)
+	for _, l := range results {
+		if l.Error != nil {
+			s.Errors += 1
+			continue
+		}
+		s.Analyzed += 1
+		commitsByRepo := 0
+		for i, commit := range l.Commits {
+			s.Commits += commit
+			commitsByRepo += commit
+			s.Merged.Commits[i] += commit
+		}
+		if commitsByRepo > 0 {
+			s.Repositories = append(s.Repositories, RepositoryCommits{
+				Folder:  l.Folder,
+				Commits: commitsByRepo,
+			})
+		}
+		s.Merged.Additions += l.Additions
+		s.Merged.Deletions += l.Deletions
+		s.Languages.add(l.Languages)
+		for i, v := range l.DayCommits {
+			s.DayCommits[i] += v
+			s.Merged.DayCommits[i] += v
+		}
+		for i, v := range l.HoursCommits {
+			s.HoursCommits[i] += v
+			s.Merged.HoursCommits[i] += v
+		}
+
+		for author, c := range l.AuthorsEditions {
+			if authors[author] == nil {
+				authors[author] = &Contributions{Author: author}
+			}
+			authors[author].Additions += c["additions"]
+			authors[author].Deletions += c["deletions"]
+		}
+	}
+
+	for _, c := range authors {
+		s.Contributors = append(s.Contributors, *c)
+	}
+	sort.Slice(s.Contributors, func(i, j int) bool {
+		if s.Contributors[i].Total() != s.Contributors[j].Total() {
+			return s.Contributors[i].Total() > s.Contributors[j].Total()
+		}
+		return s.Contributors[i].Author < s.Contributors[j].Author
+	})
+	return s
+}
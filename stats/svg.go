@@ -0,0 +1,144 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	svgCellSize   = 11
+	svgCellStride = 14
+	svgLeftMargin = 32
+	svgTopMargin  = 20
+	// svgLessWidth and svgMoreWidth are the widths of the labels around the colors legend
+	svgLessWidth = 26
+	svgMoreWidth = 60
+)
+
+// ExportSVG computes the statistics of all the folders and writes the merged
+// contributions calendar as SVG in the file `filePath`
+func ExportSVG(opts LaunchOptions, filePath string) error {
+	opts.Merge = true
+	results, err := analyzeWithProgress(opts)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return errors.New("no repository to export")
+	}
+	r := results[0]
+	if !r.analyzed() {
+		// nothing could be analyzed
+		return r.Error
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := WriteSVG(f, r); err != nil {
+		return err
+	}
+	fmt.Printf("\nContributions calendar exported to %s\n", filePath)
+	return reportFailures(opts, results)
+}
+
+// WriteSVG writes the contributions calendar of the result as a standalone SVG image:
+// one column per week, one row per weekday, with months, weekdays and a colors legend
+func WriteSVG(w io.Writer, r *StatsResult) error {
+	months := monthLabels(r, -1)
+	begin := r.gridBegin()
+	locale := r.Options.locale()
+	colors := r.Options.theme().SVGColors
+	scale := r.Options.Scale.thresholds(r)
+
+	width := svgLeftMargin + len(months)*svgCellStride + svgCellStride
+	// the legend may be wider than the grid of a short scan window
+	legendWidth := svgLessWidth + len(colors)*svgCellStride + svgMoreWidth
+	if width < legendWidth {
+		width = legendWidth
+	}
+	height := svgTopMargin + 7*svgCellStride + 2*svgCellStride
+
+	var out strings.Builder
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, Helvetica, Arial, sans-serif" font-size="9" fill="#767676">`+"\n", width, height, width, height)
+	fmt.Fprintf(&out, "<title>%s</title>\n", html.EscapeString(svgTitle(r)))
+
+	for column, label := range months {
+		if label != "" {
+			fmt.Fprintf(&out, `<text x="%d" y="%d">%s</text>`+"\n", svgLeftMargin+column*svgCellStride, svgTopMargin-8, html.EscapeString(label))
+		}
+	}
+	for row := 0; row < 7; row++ {
+		day := begin.AddDate(0, 0, row)
+		fmt.Fprintf(&out, `<text x="0" y="%d">%s</text>`+"\n", svgTopMargin+row*svgCellStride+svgCellSize-2, html.EscapeString(locale.weekday(day.Weekday())))
+	}
+
+	for column := range months {
+		for row := 0; row < 7; row++ {
+			day := begin.AddDate(0, 0, column*7+row)
+			if !r.inScan(day) {
+				continue
+			}
+			commits := r.CommitsOn(day)
+			fmt.Fprintf(
+				&out,
+				`<rect x="%d" y="%d" width="%d" height="%d" rx="2" ry="2" fill="%s" data-date="%s" data-count="%d"><title>%s</title></rect>`+"\n",
+				svgLeftMargin+column*svgCellStride,
+				svgTopMargin+row*svgCellStride,
+				svgCellSize,
+				svgCellSize,
+				colors[scale.intensity(commits)],
+				day.Format(jsonDateFormat),
+				commits,
+				svgCellTitle(commits, day.Format("Monday, January 2, 2006")),
+			)
+		}
+	}
+
+	legendY := svgTopMargin + 7*svgCellStride + svgCellStride/2
+	legendX := width - svgCellStride*len(colors) - svgMoreWidth
+	if legendX < svgLessWidth {
+		legendX = svgLessWidth
+	}
+	fmt.Fprintf(&out, `<text x="%d" y="%d">Less</text>`+"\n", legendX-svgLessWidth, legendY+svgCellSize-2)
+	for level := intensityNone; level <= intensityHigh; level++ {
+		fmt.Fprintf(&out, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" ry="2" fill="%s"/>`+"\n", legendX+int(level)*svgCellStride, legendY, svgCellSize, svgCellSize, colors[level])
+	}
+	fmt.Fprintf(&out, `<text x="%d" y="%d">More</text>`+"\n", legendX+len(colors)*svgCellStride+4, legendY+svgCellSize-2)
+	out.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// svgTitle returns the description of the calendar
+func svgTitle(r *StatsResult) string {
+	user := "all"
+	if r.Options.EmailOrUsername != nil {
+		user = *r.Options.EmailOrUsername
+	}
+	return fmt.Sprintf(
+		"Contributions of %s from %s to %s",
+		user,
+		getBeginningOfDay(r.BeginOfScan).Format("January 02, 2006"),
+		getEndOfDay(r.EndOfScan).Format("January 02, 2006"),
+	)
+}
+
+// svgCellTitle returns the tooltip of a day cell
+func svgCellTitle(commits int, day string) string {
+	switch commits {
+	case 0:
+		return fmt.Sprintf("No commits on %s", day)
+	case 1:
+		return fmt.Sprintf("1 commit on %s", day)
+	default:
+		return fmt.Sprintf("%d commits on %s", commits, day)
+	}
+}
@@ -0,0 +1,95 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestWriteSVG(tt *testing.T) {
+	t := td.NewT(tt)
+
+	results := stats.Launch(stats.LaunchOptions{
+		NoCache:         true,
+		User:            nil,
+		DurationInWeeks: 4,
+		Folders:         currentRepo,
+		Merge:           true,
+		Dashboard:       true,
+	})
+	t.CmpNoError(results[0].Error)
+
+	var out bytes.Buffer
+	t.CmpNoError(stats.WriteSVG(&out, results[0]))
+
+	// the document is well formed and has one cell per day plus the legend cells
+	decoder := xml.NewDecoder(&out)
+	rects := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !t.CmpNoError(err) {
+			return
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "rect" {
+			rects++
+		}
+	}
+	t.Cmp(rects, td.Gte(28+4))
+}
+
+func TestWriteSVGShortWindow(tt *testing.T) {
+	t := td.NewT(tt)
+
+	repo := newTestRepository(tt)
+	repo.commit("a.txt", "a", "Alice", "alice@corp.com", time.Date(2025, time.July, 1, 10, 0, 0, 0, time.Local), "first")
+	results, err := stats.NewAnalyzer(stats.LaunchOptions{
+		NoCache: true,
+		Folders: []string{repo.path},
+		Merge:   true,
+		Since:   "2025-07-01",
+		Until:   "2025-07-07",
+	}).Run(context.Background())
+	t.CmpNoError(err)
+	t.CmpNoError(results[0].Error)
+
+	var out bytes.Buffer
+	t.CmpNoError(stats.WriteSVG(&out, results[0]))
+
+	// the legend fits in the canvas, even wider than the grid of 2 weeks
+	decoder := xml.NewDecoder(&out)
+	width := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !t.CmpNoError(err) {
+			return
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			value, err := strconv.Atoi(attr.Value)
+			switch {
+			case err != nil:
+			case start.Name.Local == "svg" && attr.Name.Local == "width":
+				width = value
+			case attr.Name.Local == "x":
+				t.Cmp(value, td.Between(0, width), "%s x", start.Name.Local)
+			}
+		}
+	}
+	t.Cmp(width, td.Gt(0))
+}
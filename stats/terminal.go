@@ -2,6 +2,7 @@ package stats
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fatih/color"
 )
@@ -20,35 +21,58 @@ var (
 	Message      = TermStyle{[]color.Attribute{color.FgGreen, color.BgBlack}}
 	Error        = TermStyle{[]color.Attribute{color.FgRed}}
 	Header       = TermStyle{[]color.Attribute{color.FgMagenta}}
+	Addition     = TermStyle{[]color.Attribute{color.FgGreen}}
+	Deletion     = TermStyle{[]color.Attribute{color.FgRed}}
 )
 
+// dashboardColors are the dashboard names of the terminal colors
+var dashboardColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
 func colorize(c TermStyle, s string, oType OutputType) string {
 	switch oType {
 	case Dashboard:
-		if len(c.Attributes) > 0 {
-
-			var cType string
-			var cValue string
-
-			switch c.Attributes[0] {
-			case color.FgGreen:
-				cType = "fg"
-				cValue = "green"
-			case color.FgMagenta:
-				cType = "fg"
-				cValue = "magenta"
-			case color.FgRed:
-				cValue = "red"
-			case color.FgYellow:
-				cType = "fg"
-				cValue = "red"
-			}
-			if cType != "" {
-				return fmt.Sprintf("[%s](%s:%s)", s, cType, cValue)
-			}
+		style := dashboardStyle(c)
+		if style == "" {
+			return s
 		}
-		return s
+		return fmt.Sprintf("[%s](%s)", s, style)
 	default:
 		return color.New(c.Attributes...).SprintfFunc()(s)
 	}
 }
+
+// dashboardStyle returns the dashboard style of the terminal style,
+// bright colors are rendered bold
+func dashboardStyle(c TermStyle) string {
+	var fg, bg, modifier string
+	for _, attribute := range c.Attributes {
+		switch {
+		case attribute >= color.FgBlack && attribute <= color.FgWhite:
+			fg = dashboardColors[attribute-color.FgBlack]
+		case attribute >= color.FgHiBlack && attribute <= color.FgHiWhite:
+			fg = dashboardColors[attribute-color.FgHiBlack]
+			modifier = "bold"
+		case attribute >= color.BgBlack && attribute <= color.BgWhite:
+			bg = dashboardColors[attribute-color.BgBlack]
+		case attribute >= color.BgHiBlack && attribute <= color.BgHiWhite:
+			bg = dashboardColors[attribute-color.BgHiBlack]
+		case attribute == color.Bold:
+			modifier = "bold"
+		case attribute == color.Underline:
+			modifier = "underline"
+		case attribute == color.ReverseVideo:
+			modifier = "reverse"
+		}
+	}
+	var style []string
+	if fg != "" {
+		style = append(style, "fg:"+fg)
+	}
+	if bg != "" {
+		style = append(style, "bg:"+bg)
+	}
+	if modifier != "" {
+		style = append(style, "mod:"+modifier)
+	}
+	return strings.Join(style, ",")
+}
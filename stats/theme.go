@@ -0,0 +1,195 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Theme holds the styles of the heatmap cells
+type Theme struct {
+	Empty        TermStyle
+	Low          TermStyle
+	Middle       TermStyle
+	High         TermStyle
+	Today        TermStyle
+	FirstOfMonth TermStyle
+	// SVGColors are the cells colors of the exported images, empty cells first
+	SVGColors [4]string
+}
+
+// DefaultTheme is the theme used when none is given
+const DefaultTheme = "default"
+
+var themes = map[string]Theme{
+	DefaultTheme: {
+		Empty:        Empty,
+		Low:          ValueLow,
+		Middle:       ValueMiddle,
+		High:         ValueHigh,
+		Today:        Today,
+		FirstOfMonth: FirstOfMonth,
+		SVGColors:    [4]string{"#ebedf0", "#9be9a8", "#40c463", "#216e39"},
+	},
+	"github": {
+		Empty:        TermStyle{[]color.Attribute{color.FgHiBlack}},
+		Low:          TermStyle{[]color.Attribute{color.FgGreen}},
+		Middle:       TermStyle{[]color.Attribute{color.FgHiGreen}},
+		High:         TermStyle{[]color.Attribute{color.FgBlack, color.BgGreen}},
+		Today:        Today,
+		FirstOfMonth: FirstOfMonth,
+		SVGColors:    [4]string{"#ebedf0", "#9be9a8", "#40c463", "#216e39"},
+	},
+	"colorblind": {
+		Empty:        TermStyle{[]color.Attribute{color.FgHiBlack}},
+		Low:          TermStyle{[]color.Attribute{color.FgCyan}},
+		Middle:       TermStyle{[]color.Attribute{color.FgBlue}},
+		High:         TermStyle{[]color.Attribute{color.FgYellow, color.Bold}},
+		Today:        TermStyle{[]color.Attribute{color.FgWhite, color.BgBlue}},
+		FirstOfMonth: TermStyle{[]color.Attribute{color.Underline}},
+		SVGColors:    [4]string{"#ebedf0", "#9ecae1", "#3182bd", "#e6550d"},
+	},
+	"monochrome": {
+		Empty:        TermStyle{[]color.Attribute{color.Faint}},
+		Low:          TermStyle{[]color.Attribute{color.Faint}},
+		Middle:       TermStyle{[]color.Attribute{}},
+		High:         TermStyle{[]color.Attribute{color.Bold}},
+		Today:        TermStyle{[]color.Attribute{color.ReverseVideo}},
+		FirstOfMonth: TermStyle{[]color.Attribute{color.Underline}},
+		SVGColors:    [4]string{"#eeeeee", "#bdbdbd", "#757575", "#212121"},
+	},
+	"high-contrast": {
+		Empty:        TermStyle{[]color.Attribute{}},
+		Low:          TermStyle{[]color.Attribute{color.FgHiWhite, color.Bold}},
+		Middle:       TermStyle{[]color.Attribute{color.FgBlack, color.BgYellow}},
+		High:         TermStyle{[]color.Attribute{color.FgWhite, color.BgRed, color.Bold}},
+		Today:        TermStyle{[]color.Attribute{color.FgBlack, color.BgWhite}},
+		FirstOfMonth: TermStyle{[]color.Attribute{color.FgCyan, color.Underline}},
+		SVGColors:    [4]string{"#ffffff", "#ffd600", "#ff3d00", "#000000"},
+	},
+}
+
+// ParseTheme returns the theme named `name`, the default one if empty
+func ParseTheme(name string) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	theme, ok := themes[name]
+	if !ok {
+		return themes[DefaultTheme], fmt.Errorf("unknown theme %s, use one of: %s", name, strings.Join(Themes(), ", "))
+	}
+	return theme, nil
+}
+
+// Themes returns the names of the available themes
+func Themes() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// theme returns the theme of the options, the default one if not valid
+func (o StatsOptions) theme() Theme {
+	theme, _ := ParseTheme(o.Theme)
+	return theme
+}
+
+// style returns the style of the cells of the intensity
+func (t Theme) style(level intensity) TermStyle {
+	switch level {
+	case intensityNone:
+		return t.Empty
+	case intensityLow:
+		return t.Low
+	case intensityMiddle:
+		return t.Middle
+	default:
+		return t.High
+	}
+}
+
+// Scale holds the thresholds of the heatmap intensities: days with less commits
+// than Middle are low, days with at least High commits are high
+type Scale struct {
+	// Quantile computes the thresholds from the days commits of each result,
+	// splitting the active days in three groups of the same size
+	Quantile bool
+	Middle   int
+	High     int
+}
+
+// DefaultScale is the scale used when the thresholds are not set
+var DefaultScale = Scale{Middle: 5, High: 10}
+
+// ParseScale returns the scale matching the `--scale` parameter value:
+// `fixed`, `quantile` or the middle and high thresholds (3,8)
+func ParseScale(value string) (Scale, error) {
+	switch value {
+	case "", "fixed":
+		return DefaultScale, nil
+	case "quantile":
+		return Scale{Quantile: true}, nil
+	}
+	thresholds := strings.Split(value, ",")
+	if len(thresholds) != 2 {
+		return DefaultScale, fmt.Errorf("invalid scale %s, use fixed, quantile or the middle and high thresholds like 3,8", value)
+	}
+	middle, err := strconv.Atoi(strings.TrimSpace(thresholds[0]))
+	if err != nil {
+		return DefaultScale, fmt.Errorf("invalid scale middle threshold %s", thresholds[0])
+	}
+	high, err := strconv.Atoi(strings.TrimSpace(thresholds[1]))
+	if err != nil {
+		return DefaultScale, fmt.Errorf("invalid scale high threshold %s", thresholds[1])
+	}
+	if middle < 2 || high < middle {
+		return DefaultScale, fmt.Errorf("invalid scale %s, thresholds must be increasing and greater than 1", value)
+	}
+	return Scale{Middle: middle, High: high}, nil
+}
+
+// thresholds returns the scale applied to the result: quantiles are computed from
+// its days commits, unset thresholds are the default ones
+func (s Scale) thresholds(r *StatsResult) Scale {
+	if !s.Quantile {
+		if s.Middle <= 0 || s.High <= 0 {
+			return DefaultScale
+		}
+		return s
+	}
+	var values []int
+	for _, commits := range r.Commits {
+		if commits > 0 {
+			values = append(values, commits)
+		}
+	}
+	if len(values) == 0 {
+		return DefaultScale
+	}
+	sort.Ints(values)
+	// the days with as many commits as the last day of a group stay in that group
+	return Scale{
+		Middle: values[(len(values)-1)/3] + 1,
+		High:   values[(len(values)-1)*2/3] + 1,
+	}
+}
+
+// intensity returns the activity level of a day with `val` commits
+func (s Scale) intensity(val int) intensity {
+	switch {
+	case val <= 0:
+		return intensityNone
+	case val < s.Middle:
+		return intensityLow
+	case val < s.High:
+		return intensityMiddle
+	default:
+		return intensityHigh
+	}
+}
@@ -0,0 +1,84 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestParseScale(tt *testing.T) {
+	t := td.NewT(tt)
+
+	scale, err := stats.ParseScale("")
+	t.CmpNoError(err)
+	t.Cmp(scale, stats.DefaultScale)
+
+	scale, err = stats.ParseScale("quantile")
+	t.CmpNoError(err)
+	t.True(scale.Quantile)
+
+	scale, err = stats.ParseScale("3, 8")
+	t.CmpNoError(err)
+	t.Cmp(scale, stats.Scale{Middle: 3, High: 8})
+
+	for _, value := range []string{"3", "8,3", "1,2", "a,b", "linear"} {
+		_, err = stats.ParseScale(value)
+		t.CmpError(err, value)
+	}
+
+	_, err = stats.ParseTheme("solarized")
+	t.Cmp(err, td.Contains("unknown theme solarized"))
+	t.Cmp(stats.Themes(), td.SuperBagOf("default", "github", "colorblind", "monochrome", "high-contrast"))
+}
+
+func TestHeatmapScale(tt *testing.T) {
+	t := td.NewT(tt)
+
+	repo := newTestRepository(tt)
+	for day, commits := range []int{1, 2, 6} {
+		when := time.Date(2025, time.March, 10+day, 12, 0, 0, 0, time.Local)
+		for i := 0; i < commits; i++ {
+			repo.commit("file.txt", fmt.Sprintf("%d-%d", day, i), "Alice", "alice@corp.com", when, "commit")
+		}
+	}
+
+	// colorsByCount returns the color of the cells of each number of commits
+	cells := regexp.MustCompile(`fill="(#[0-9a-f]+)" data-date="[^"]+" data-count="(\d+)"`)
+	colorsByCount := func(scale stats.Scale) map[int]string {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache: true,
+			Folders: []string{repo.path},
+			Merge:   true,
+			Since:   "2025-03",
+			Until:   "2025-03",
+			Theme:   "monochrome",
+			Scale:   scale,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+
+		var out bytes.Buffer
+		t.CmpNoError(stats.WriteSVG(&out, results[0]))
+		colors := make(map[int]string)
+		for _, match := range cells.FindAllStringSubmatch(out.String(), -1) {
+			count, _ := strconv.Atoi(match[2])
+			colors[count] = match[1]
+		}
+		return colors
+	}
+
+	theme, _ := stats.ParseTheme("monochrome")
+	none, low, middle, high := theme.SVGColors[0], theme.SVGColors[1], theme.SVGColors[2], theme.SVGColors[3]
+
+	t.Cmp(colorsByCount(stats.DefaultScale), map[int]string{0: none, 1: low, 2: low, 6: middle})
+	t.Cmp(colorsByCount(stats.Scale{}), map[int]string{0: none, 1: low, 2: low, 6: middle}, "unset thresholds are the default ones")
+	t.Cmp(colorsByCount(stats.Scale{Quantile: true}), map[int]string{0: none, 1: low, 2: middle, 6: high})
+	t.Cmp(colorsByCount(stats.Scale{Middle: 6, High: 20}), map[int]string{0: none, 1: low, 2: low, 6: middle})
+}
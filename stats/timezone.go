@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// AuthorTimezone keeps the commits timestamps in the time zone of their author
+	AuthorTimezone = "author"
+	// LocalTimezone converts the commits timestamps to the time zone of the machine
+	LocalTimezone = "local"
+)
+
+// loadTimezone returns the location matching the `--timezone` parameter value:
+// an IANA time zone name, `local` or `author`. It is nil for `author`.
+func loadTimezone(name string) (*time.Location, error) {
+	switch name {
+	case "", AuthorTimezone:
+		return nil, nil
+	case LocalTimezone:
+		return time.Local, nil
+	default:
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %s, use an IANA name (Europe/Paris), local or author: %w", name, err)
+		}
+		return location, nil
+	}
+}
+
+// scanNow returns the current time in the `timezone`, in the local one for `author`
+func scanNow(timezone string) time.Time {
+	location, _ := loadTimezone(timezone)
+	if location == nil {
+		return time.Now()
+	}
+	return time.Now().In(location)
+}
+
+// inTimezone returns the commit timestamp `when` converted to the `location`,
+// unchanged if nil
+func inTimezone(when time.Time, location *time.Location) time.Time {
+	if location == nil {
+		return when
+	}
+	return when.In(location)
+}
+
+// timezoneLabel returns the description of the time zone of the options
+func (o StatsOptions) timezoneLabel() string {
+	switch o.Timezone {
+	case "", AuthorTimezone:
+		return "author time zones"
+	case LocalTimezone:
+		zone, _ := time.Now().Zone()
+		return fmt.Sprintf("local time zone (%s)", zone)
+	default:
+		return o.Timezone
+	}
+}
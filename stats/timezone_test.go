@@ -0,0 +1,50 @@
+package stats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/maxatome/go-testdeep/td"
+	"github.com/svandecappelle/gitcontrib/stats"
+)
+
+func TestTimezone(tt *testing.T) {
+	t := td.NewT(tt)
+
+	paris := time.FixedZone("CEST", 2*60*60)
+	montreal := time.FixedZone("EDT", -4*60*60)
+	repo := newTestRepository(tt)
+	repo.commit("a.txt", "a", "Alice", "alice@corp.com", time.Date(2025, time.June, 13, 23, 30, 0, 0, paris), "late in Paris")
+	repo.commit("b.txt", "b", "Bob", "bob@corp.com", time.Date(2025, time.June, 13, 9, 0, 0, 0, montreal), "morning in Montreal")
+
+	run := func(timezone string) *stats.StatsResult {
+		results, err := stats.NewAnalyzer(stats.LaunchOptions{
+			NoCache:  true,
+			Folders:  []string{repo.path},
+			Since:    "2025-06",
+			Until:    "2025-06",
+			Timezone: timezone,
+		}).Run(context.Background())
+		t.CmpNoError(err)
+		t.CmpNoError(results[0].Error)
+		return results[0]
+	}
+
+	r := run(stats.AuthorTimezone)
+	t.Cmp(r.HoursCommits[23], 1)
+	t.Cmp(r.HoursCommits[9], 1)
+	t.Cmp(r.DayCommits[time.Friday], 2)
+
+	r = run("Asia/Kolkata")
+	t.Cmp(r.BeginOfScan.Location().String(), "Asia/Kolkata")
+	t.Cmp(r.HoursCommits[3], 1, "23:30 CEST is 03:00 IST")
+	t.Cmp(r.HoursCommits[18], 1, "09:00 EDT is 18:30 IST")
+	t.Cmp(r.DayCommits[time.Friday], 1)
+	t.Cmp(r.DayCommits[time.Saturday], 1)
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	t.Cmp(r.CommitsOn(time.Date(2025, time.June, 14, 0, 0, 0, 0, kolkata)), 1)
+
+	_, err := stats.NewAnalyzer(stats.LaunchOptions{Folders: []string{repo.path}, Timezone: "Mars/Olympus"}).Run(context.Background())
+	t.Cmp(err, td.Contains("invalid time zone Mars/Olympus"))
+}